@@ -0,0 +1,197 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"sigs.k8s.io/controller-runtime/pkg/conversion"
+
+	v2 "github.com/nazman/cert-trust/api/v2"
+)
+
+// unconvertedAnnotation keeps the v1 fields of a CertificateImport that v2
+// has no place for, so that a v1 object survives a round trip through v2
+// unchanged. They have no effect in v1 either: a targetSecret next to a
+// targetConfigMap, and splitCABundleCAOnly without splitCABundle.
+const unconvertedAnnotation = "cert-trust.flolive.io/v1-unconverted"
+
+// unconvertedFields are the fields kept in unconvertedAnnotation.
+type unconvertedFields struct {
+	TargetSecret        string `json:"targetSecret,omitempty"`
+	SplitCABundleCAOnly bool   `json:"splitCABundleCAOnly,omitempty"`
+}
+
+// ConvertTo converts this CertificateImport to the v2 hub version.
+func (src *CertificateImport) ConvertTo(dstRaw conversion.Hub) error {
+	dst, ok := dstRaw.(*v2.CertificateImport)
+	if !ok {
+		return fmt.Errorf("unsupported conversion of CertificateImport to %T", dstRaw)
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	s := src.Spec.DeepCopy()
+	var unconverted unconvertedFields
+
+	dst.Spec = v2.CertificateImportSpec{
+		Source: v2.ImportSource{
+			Export:              s.FromExport,
+			CABundleExports:     s.FromExports,
+			ExpectedFingerprint: s.ExpectedFingerprint,
+			WaitForValid:        s.WaitForValidSource,
+			WaitForData:         s.WaitForSourceData,
+			VerifyKeyPair:       s.VerifyKeyPair,
+		},
+		Target: v2.TargetRef{
+			Key:         s.TargetConfigMapKey,
+			Type:        s.TargetType,
+			Labels:      s.TargetLabels,
+			Annotations: s.TargetAnnotations,
+			Immutable:   s.ImmutableTarget,
+		},
+		Keys: v2.KeySelector{
+			Include:      s.IncludeKeys,
+			Exclude:      s.ExcludeKeys,
+			Rename:       s.KeyMap,
+			NormalizePEM: s.NormalizePEM,
+		},
+		Keystores: v2.Keystores{
+			PKCS12: convertKeystoreTo(s.PKCS12),
+			JKS:    convertKeystoreTo(s.JKS),
+		},
+		Schedule:    s.Schedule,
+		Timezone:    s.Timezone,
+		Jitter:      s.Jitter,
+		SyncOnStart: s.SyncOnStart,
+		Suspend:     s.Suspend,
+	}
+	// a configmap target wins, as in the controller
+	switch {
+	case s.TargetConfigMap != "":
+		dst.Spec.Target.Kind, dst.Spec.Target.Name = v2.TargetKindConfigMap, s.TargetConfigMap
+		unconverted.TargetSecret = s.TargetSecret
+	case s.TargetSecret != "":
+		dst.Spec.Target.Kind, dst.Spec.Target.Name = v2.TargetKindSecret, s.TargetSecret
+	}
+	if s.SplitCABundle {
+		dst.Spec.Keys.SplitCABundle = &v2.CABundleSplit{CAOnly: s.SplitCABundleCAOnly}
+	} else {
+		unconverted.SplitCABundleCAOnly = s.SplitCABundleCAOnly
+	}
+	for _, ref := range s.RolloutTargets {
+		dst.Spec.RolloutTargets = append(dst.Spec.RolloutTargets, v2.WorkloadRef{Kind: ref.Kind, Name: ref.Name})
+	}
+
+	if unconverted != (unconvertedFields{}) {
+		raw, err := json.Marshal(unconverted)
+		if err != nil {
+			return err
+		}
+		if dst.Annotations == nil {
+			dst.Annotations = map[string]string{}
+		}
+		dst.Annotations[unconvertedAnnotation] = string(raw)
+	}
+
+	dst.Status = v2.CertificateImportStatus(*src.Status.DeepCopy())
+	return nil
+}
+
+// ConvertFrom converts the v2 hub version of a CertificateImport to this
+// version.
+func (dst *CertificateImport) ConvertFrom(srcRaw conversion.Hub) error {
+	src, ok := srcRaw.(*v2.CertificateImport)
+	if !ok {
+		return fmt.Errorf("unsupported conversion of %T to CertificateImport", srcRaw)
+	}
+	dst.ObjectMeta = *src.ObjectMeta.DeepCopy()
+	s := src.Spec.DeepCopy()
+
+	var unconverted unconvertedFields
+	if raw, ok := dst.Annotations[unconvertedAnnotation]; ok {
+		if err := json.Unmarshal([]byte(raw), &unconverted); err != nil {
+			return fmt.Errorf("invalid %s annotation: %w", unconvertedAnnotation, err)
+		}
+		delete(dst.Annotations, unconvertedAnnotation)
+		if len(dst.Annotations) == 0 {
+			dst.Annotations = nil
+		}
+	}
+
+	dst.Spec = CertificateImportSpec{
+		FromExport:          s.Source.Export,
+		FromExports:         s.Source.CABundleExports,
+		ExpectedFingerprint: s.Source.ExpectedFingerprint,
+		WaitForValidSource:  s.Source.WaitForValid,
+		WaitForSourceData:   s.Source.WaitForData,
+		VerifyKeyPair:       s.Source.VerifyKeyPair,
+		TargetConfigMapKey:  s.Target.Key,
+		TargetType:          s.Target.Type,
+		TargetLabels:        s.Target.Labels,
+		TargetAnnotations:   s.Target.Annotations,
+		ImmutableTarget:     s.Target.Immutable,
+		IncludeKeys:         s.Keys.Include,
+		ExcludeKeys:         s.Keys.Exclude,
+		KeyMap:              s.Keys.Rename,
+		NormalizePEM:        s.Keys.NormalizePEM,
+		SplitCABundleCAOnly: unconverted.SplitCABundleCAOnly,
+		PKCS12:              convertKeystoreFrom(s.Keystores.PKCS12),
+		JKS:                 convertKeystoreFrom(s.Keystores.JKS),
+		Schedule:            s.Schedule,
+		Timezone:            s.Timezone,
+		Jitter:              s.Jitter,
+		SyncOnStart:         s.SyncOnStart,
+		Suspend:             s.Suspend,
+	}
+	switch s.Target.Kind {
+	case v2.TargetKindConfigMap:
+		dst.Spec.TargetConfigMap = s.Target.Name
+		dst.Spec.TargetSecret = unconverted.TargetSecret
+	case v2.TargetKindSecret:
+		dst.Spec.TargetSecret = s.Target.Name
+	}
+	if split := s.Keys.SplitCABundle; split != nil {
+		dst.Spec.SplitCABundle = true
+		dst.Spec.SplitCABundleCAOnly = split.CAOnly
+	}
+	for _, ref := range s.RolloutTargets {
+		dst.Spec.RolloutTargets = append(dst.Spec.RolloutTargets, WorkloadRef{Kind: ref.Kind, Name: ref.Name})
+	}
+
+	dst.Status = CertificateImportStatus(*src.Status.DeepCopy())
+	return nil
+}
+
+func convertKeystoreTo(in *KeystoreOutput) *v2.KeystoreOutput {
+	if in == nil {
+		return nil
+	}
+	return &v2.KeystoreOutput{
+		Key:               in.Key,
+		Alias:             in.Alias,
+		PasswordSecretRef: v2.SecretKeyRef(in.PasswordSecretRef),
+	}
+}
+
+func convertKeystoreFrom(in *v2.KeystoreOutput) *KeystoreOutput {
+	if in == nil {
+		return nil
+	}
+	return &KeystoreOutput{
+		Key:               in.Key,
+		Alias:             in.Alias,
+		PasswordSecretRef: SecretKeyRef(in.PasswordSecretRef),
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	v2 "github.com/nazman/cert-trust/api/v2"
+)
+
+func TestCertificateImportRoundTrip(t *testing.T) {
+	yes := true
+	now := metav1.NewTime(time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC))
+	meta := metav1.ObjectMeta{
+		Namespace:   "frontend",
+		Name:        "app",
+		Labels:      map[string]string{"team": "web"},
+		Annotations: map[string]string{"note": "kept"},
+	}
+	status := CertificateImportStatus{
+		LastSyncTime: &now,
+		SyncCount:    3,
+		NotAfter:     &now,
+		Conditions: []metav1.Condition{{
+			Type: "Ready", Status: metav1.ConditionTrue, Reason: "Synced", LastTransitionTime: now,
+		}},
+	}
+
+	tests := []struct {
+		name string
+		spec CertificateImportSpec
+		// wantUnconverted is whether v2 needs the annotation to keep a field
+		wantUnconverted bool
+	}{
+		{
+			name: "minimal secret target",
+			spec: CertificateImportSpec{FromExport: "backend/app", TargetSecret: "app-tls"},
+		},
+		{
+			name: "every secret target field",
+			spec: CertificateImportSpec{
+				FromExport:          "backend/app",
+				FromExports:         []string{"cluster/root-ca"},
+				TargetSecret:        "app-tls",
+				Schedule:            "*/5 * * * *",
+				Timezone:            "Europe/Berlin",
+				Jitter:              &metav1.Duration{Duration: time.Minute},
+				IncludeKeys:         []string{"tls.crt", "tls.key", "ca.crt"},
+				ExcludeKeys:         []string{"ca.crt"},
+				KeyMap:              map[string]string{"tls.crt": "cert.pem"},
+				SplitCABundle:       true,
+				SplitCABundleCAOnly: true,
+				TargetType:          corev1.SecretTypeOpaque,
+				TargetLabels:        map[string]string{"app": "web"},
+				TargetAnnotations:   map[string]string{"reloader": "true"},
+				PKCS12:              &KeystoreOutput{Key: "keystore.p12", PasswordSecretRef: SecretKeyRef{Name: "pw"}},
+				JKS:                 &KeystoreOutput{Key: "keystore.jks", Alias: "app", PasswordSecretRef: SecretKeyRef{Name: "pw", Key: "jks"}},
+				VerifyKeyPair:       &yes,
+				NormalizePEM:        true,
+				WaitForValidSource:  true,
+				WaitForSourceData:   true,
+				ExpectedFingerprint: "ab:cd",
+				ImmutableTarget:     true,
+				RolloutTargets:      []WorkloadRef{{Kind: "Deployment", Name: "web"}},
+				SyncOnStart:         &yes,
+				Suspend:             true,
+			},
+		},
+		{
+			name: "configmap target",
+			spec: CertificateImportSpec{
+				FromExports:        []string{"backend/app", "cluster/root-ca"},
+				TargetConfigMap:    "ca-bundle",
+				TargetConfigMapKey: "ca.pem",
+			},
+		},
+		{
+			name: "secret next to a configmap target",
+			spec: CertificateImportSpec{
+				FromExports:     []string{"backend/app"},
+				TargetConfigMap: "ca-bundle",
+				TargetSecret:    "ignored",
+			},
+			wantUnconverted: true,
+		},
+		{
+			name: "CA only without splitting",
+			spec: CertificateImportSpec{
+				FromExport:          "backend/app",
+				TargetSecret:        "app-tls",
+				SplitCABundleCAOnly: true,
+			},
+			wantUnconverted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := &CertificateImport{ObjectMeta: *meta.DeepCopy(), Spec: tt.spec, Status: status}
+			orig := in.DeepCopy()
+
+			var hub v2.CertificateImport
+			if err := in.ConvertTo(&hub); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(in, orig) {
+				t.Fatal("ConvertTo modified its receiver")
+			}
+			if _, ok := hub.Annotations[unconvertedAnnotation]; ok != tt.wantUnconverted {
+				t.Errorf("got %s annotation %v, want %v", unconvertedAnnotation, ok, tt.wantUnconverted)
+			}
+
+			var out CertificateImport
+			if err := out.ConvertFrom(&hub); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&out, orig) {
+				t.Errorf("v1 -> v2 -> v1 changed the object\n got: %+v\nwant: %+v", out.Spec, orig.Spec)
+			}
+
+			// the hub converted back to v2 must not change either
+			var hub2 v2.CertificateImport
+			if err := out.ConvertTo(&hub2); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(&hub2, &hub) {
+				t.Errorf("v2 -> v1 -> v2 changed the object\n got: %+v\nwant: %+v", hub2.Spec, hub.Spec)
+			}
+		})
+	}
+}
+
+func TestCertificateImportConvertFromInvalidAnnotation(t *testing.T) {
+	hub := &v2.CertificateImport{ObjectMeta: metav1.ObjectMeta{
+		Annotations: map[string]string{unconvertedAnnotation: "{"},
+	}}
+	if err := (&CertificateImport{}).ConvertFrom(hub); err == nil {
+		t.Fatal("converted an object with a malformed annotation")
+	}
+}
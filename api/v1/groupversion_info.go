@@ -0,0 +1,42 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 contains the cert.trust.flolive.io/v1 API types.
+// +kubebuilder:object:generate=true
+// +groupName=cert.trust.flolive.io
+package v1
+
+import (
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+)
+
+var (
+	// GroupVersion is the group and version of the API types.
+	GroupVersion = schema.GroupVersion{Group: "cert.trust.flolive.io", Version: "v1"}
+
+	// SchemeBuilder registers the API types with a scheme.
+	SchemeBuilder = &scheme.Builder{GroupVersion: GroupVersion}
+
+	// AddToScheme adds the API types to a scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+func init() {
+	SchemeBuilder.Register(
+		&CertificateExport{}, &CertificateExportList{},
+		&CertificateImport{}, &CertificateImportList{},
+		&ClusterCertificateExport{}, &ClusterCertificateExportList{},
+	)
+}
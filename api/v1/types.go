@@ -15,6 +15,7 @@
 package v1
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
@@ -22,31 +23,91 @@ import (
 // +kubebuilder:subresource:status
 // +kubebuilder:resource:scope=Namespaced,shortName=cex
 // +kubebuilder:printcolumn:name=Secret,JSONPath=.spec.secretRef,description=Source TLS secret,type=string
+// +kubebuilder:printcolumn:name=Source Valid,JSONPath=`.status.conditions[?(@.type=="SourceValid")].status`,description=Whether the source secret is valid,type=string
+// +kubebuilder:printcolumn:name=Schedule,JSONPath=.spec.schedule,description=Cron schedule,type=string
+// +kubebuilder:printcolumn:name=Expiry,JSONPath=.status.notAfter,description=Leaf certificate expiry,type=date
+// +kubebuilder:printcolumn:name=Next,JSONPath=.status.nextSyncTime,description=Next scheduled push or validation,type=date
+// +kubebuilder:printcolumn:name=Suspended,JSONPath=.spec.suspend,description=Whether syncing is paused,type=boolean
 // CertificateExport specifies a source secret to export from this namespace
 // to other namespaces.
 type CertificateExport struct {
-	metav1.TypeMeta   `json:\",inline\"`
-	metav1.ObjectMeta `json:\"metadata,omitempty\"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   CertificateExportSpec   `json:\"spec,omitempty\"`
-	Status CertificateExportStatus `json:\"status,omitempty\"`
+	Spec   CertificateExportSpec   `json:"spec,omitempty"`
+	Status CertificateExportStatus `json:"status,omitempty"`
 }
 
 type CertificateExportSpec struct {
-	// SecretRef is the name of a TLS secret in the same namespace
-	SecretRef string `json:\"secretRef\"`
+	// SecretRef is the name of a TLS or dockerconfigjson secret in the same
+	// namespace
+	SecretRef string `json:"secretRef"`
+	// AllowOpaque also accepts an Opaque source secret, e.g. CA-only trust
+	// material, which is copied as is. Defaults to false
+	AllowOpaque bool `json:"allowOpaque,omitempty"`
+	// Schedule is a cron expression determining when to push to target
+	// namespaces, or to validate the source secret when not pushing
+	Schedule string `json:"schedule,omitempty"`
+	// Timezone is an IANA zone (e.g. America/New_York) Schedule is evaluated in.
+	// Defaults to the controller's local time
+	Timezone string `json:"timezone,omitempty"`
+	// TargetSecret is the name of the secret pushed into each target namespace.
+	// Pushing is disabled when empty.
+	TargetSecret string `json:"targetSecret,omitempty"`
+	// TargetNamespaces lists namespaces to push the secret into
+	TargetNamespaces []string `json:"targetNamespaces,omitempty"`
+	// TargetNamespaceSelector selects additional namespaces to push the secret into
+	TargetNamespaceSelector *metav1.LabelSelector `json:"targetNamespaceSelector,omitempty"`
+	// Suspend pauses pushing without deleting the export
+	Suspend bool `json:"suspend,omitempty"`
+	// AllowedNamespaces limits the namespaces whose imports may copy from this
+	// export. Imports in the export's namespace are always allowed. When both
+	// this and AllowedNamespaceSelector are empty, every namespace is allowed
+	AllowedNamespaces []string `json:"allowedNamespaces,omitempty"`
+	// AllowedNamespaceSelector allows imports from namespaces matching it, in
+	// addition to AllowedNamespaces
+	AllowedNamespaceSelector *metav1.LabelSelector `json:"allowedNamespaceSelector,omitempty"`
+	// Remote, when set, pushes into another cluster instead of this one
+	Remote *RemoteTarget `json:"remote,omitempty"`
+}
+
+// RemoteTarget describes the cluster a CertificateExport pushes into.
+type RemoteTarget struct {
+	// KubeconfigSecretRef is the name of a secret in the export's namespace
+	// holding a kubeconfig for the remote cluster
+	KubeconfigSecretRef string `json:"kubeconfigSecretRef"`
+	// KubeconfigKey is the key of the kubeconfig in that secret. Defaults to kubeconfig
+	KubeconfigKey string `json:"kubeconfigKey,omitempty"`
 }
 
 type CertificateExportStatus struct {
 	// LastSyncTime records the most recent successful sync time
-	LastSyncTime *metav1.Time `json:\"lastSyncTime,omitempty\"`
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// SyncCount is the number of successful syncs
+	SyncCount int64 `json:"syncCount,omitempty"`
+	// ObservedGeneration is the metadata.generation acted upon by the most
+	// recent successful sync
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// NextSyncTime is when the next scheduled push or validation runs,
+	// cleared while suspended
+	NextSyncTime *metav1.Time `json:"nextSyncTime,omitempty"`
+	// NotBefore is the start of the validity period of the leaf certificate
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// NotAfter is the expiry of the leaf certificate
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// Importers lists the namespace/name of every CertificateImport
+	// referencing the export, sorted
+	Importers []string `json:"importers,omitempty"`
+	// Conditions describe the current state of the export, e.g. Suspended
+	// or SourceValid
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 type CertificateExportList struct {
-	metav1.TypeMeta `json:\",inline\"`
-	metav1.ListMeta `json:\"metadata,omitempty\"`
-	Items           []CertificateExport `json:\"items\"`
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateExport `json:"items"`
 }
 
 // +kubebuilder:object:root=true
@@ -55,33 +116,223 @@ type CertificateExportList struct {
 // +kubebuilder:printcolumn:name=From,JSONPath=.spec.fromExport,description=Source export,type=string
 // +kubebuilder:printcolumn:name=Target,JSONPath=.spec.targetSecret,description=Target secret,type=string
 // +kubebuilder:printcolumn:name=Schedule,JSONPath=.spec.schedule,description=Cron schedule,type=string
+// +kubebuilder:printcolumn:name=Expiry,JSONPath=.status.notAfter,description=Leaf certificate expiry,type=date
+// +kubebuilder:printcolumn:name=Next,JSONPath=.status.nextSyncTime,description=Next scheduled sync,type=date
+// +kubebuilder:printcolumn:name=Suspended,JSONPath=.spec.suspend,description=Whether syncing is paused,type=boolean
 // CertificateImport references a CertificateExport and manages a target secret
 // in this namespace.
 type CertificateImport struct {
-	metav1.TypeMeta   `json:\",inline\"`
-	metav1.ObjectMeta `json:\"metadata,omitempty\"`
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
 
-	Spec   CertificateImportSpec   `json:\"spec,omitempty\"`
-	Status CertificateImportStatus `json:\"status,omitempty\"`
+	Spec   CertificateImportSpec   `json:"spec,omitempty"`
+	Status CertificateImportStatus `json:"status,omitempty"`
 }
 
 type CertificateImportSpec struct {
-	// FromExport is in the format namespace/name or just name (same namespace)
-	FromExport string `json:\"fromExport\"`
+	// FromExport is in the format namespace/name or just name (same namespace),
+	// or cluster/name to reference a ClusterCertificateExport. Defaults to the
+	// controller's --default-from-export for imports with TargetSecret
+	FromExport string `json:"fromExport,omitempty"`
 	// TargetSecret is the name of the secret to create/update in this namespace
-	TargetSecret string `json:\"targetSecret\"`
+	TargetSecret string `json:"targetSecret,omitempty"`
+	// FromExports lists additional exports whose ca.crt is bundled into
+	// TargetConfigMap, or merged into the ca.crt of TargetSecret next to the
+	// tls.crt and tls.key of FromExport, in the same format as FromExport
+	FromExports []string `json:"fromExports,omitempty"`
+	// TargetConfigMap is the name of a configmap in this namespace that receives
+	// the deduplicated CA bundle of all referenced exports instead of a secret
+	TargetConfigMap string `json:"targetConfigMap,omitempty"`
+	// TargetConfigMapKey is the key of the bundle in TargetConfigMap. Defaults to ca-bundle.crt
+	TargetConfigMapKey string `json:"targetConfigMapKey,omitempty"`
 	// Schedule is a cron expression determining when to refresh data from the source
-	Schedule string `json:\"schedule,omitempty\"`
+	Schedule string `json:"schedule,omitempty"`
+	// Timezone is an IANA zone (e.g. America/New_York) Schedule is evaluated in.
+	// Defaults to the controller's local time
+	Timezone string `json:"timezone,omitempty"`
+	// Jitter delays each scheduled sync by a stable amount up to this duration
+	// (e.g. "5m"), derived from the import's UID. Overrides --sync-jitter
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+	// IncludeKeys, when set, limits the copied data to these source keys
+	IncludeKeys []string `json:"includeKeys,omitempty"`
+	// ExcludeKeys lists source keys to skip; ignored when IncludeKeys is set
+	ExcludeKeys []string `json:"excludeKeys,omitempty"`
+	// KeyMap renames keys when copying, from the source key to the target key
+	// (e.g. tls.crt: cert.pem). Keys not listed keep their name. Applied after
+	// IncludeKeys/ExcludeKeys; every listed key must be copied. Target keys
+	// may be Go templates using .ImportName, .Namespace and .SourceKey
+	KeyMap map[string]string `json:"keyMap,omitempty"`
+	// SplitCABundle writes each certificate of the source ca.crt to its own
+	// key, ca-0.crt, ca-1.crt, ... in bundle order, in addition to the copied keys
+	SplitCABundle bool `json:"splitCABundle,omitempty"`
+	// SplitCABundleCAOnly skips certificates that are not CA certificates
+	// when splitting
+	SplitCABundleCAOnly bool `json:"splitCABundleCAOnly,omitempty"`
+	// TargetType is the type of the target secret. When empty it is inferred
+	// from the copied data: kubernetes.io/tls when it has tls.crt and tls.key,
+	// kubernetes.io/dockerconfigjson when it has .dockerconfigjson, or Opaque
+	// otherwise.
+	TargetType corev1.SecretType `json:"targetType,omitempty"`
+	// TargetLabels are merged into the labels of the target secret
+	TargetLabels map[string]string `json:"targetLabels,omitempty"`
+	// TargetAnnotations are merged into the annotations of the target secret
+	TargetAnnotations map[string]string `json:"targetAnnotations,omitempty"`
+	// PKCS12 adds a PKCS#12 keystore built from tls.crt, tls.key and ca.crt
+	// to the target secret
+	PKCS12 *KeystoreOutput `json:"pkcs12,omitempty"`
+	// JKS adds a Java KeyStore built from tls.crt, tls.key and ca.crt to the
+	// target secret
+	JKS *KeystoreOutput `json:"jks,omitempty"`
+	// VerifyKeyPair checks that tls.crt and tls.key form a valid pair before
+	// copying them. Defaults to true
+	VerifyKeyPair *bool `json:"verifyKeyPair,omitempty"`
+	// NormalizePEM re-encodes PEM values of the copied keys with LF line
+	// endings and a single trailing newline. Non-PEM values are copied as is
+	NormalizePEM bool `json:"normalizePEM,omitempty"`
+	// WaitForValidSource skips the sync, and retries it, while the source
+	// certificate is empty, unparseable, not yet valid or expired
+	WaitForValidSource bool `json:"waitForValidSource,omitempty"`
+	// WaitForSourceData skips the sync, and retries it, while the source
+	// secret has no data yet, e.g. a placeholder of a SealedSecret or
+	// ExternalSecret: an empty tls.crt or tls.key for a TLS source
+	WaitForSourceData bool `json:"waitForSourceData,omitempty"`
+	// ExpectedFingerprint is the hex SHA-256 of the DER of the source leaf
+	// certificate. When set, a source with another leaf is not mirrored.
+	// Colons and case are ignored
+	ExpectedFingerprint string `json:"expectedFingerprint,omitempty"`
+	// ImmutableTarget marks the target secret immutable. Its data is then
+	// changed by deleting and recreating the secret
+	ImmutableTarget bool `json:"immutableTarget,omitempty"`
+	// SyncOnStart syncs the import once when the controller starts, or when
+	// the import is created later, in addition to its schedule. Defaults to
+	// the --immediate-sync-on-start flag
+	SyncOnStart *bool `json:"syncOnStart,omitempty"`
+	// Suspend pauses syncing without deleting the import
+	Suspend bool `json:"suspend,omitempty"`
+	// RolloutTargets are restarted whenever a sync changes the data of an
+	// existing target secret
+	RolloutTargets []WorkloadRef `json:"rolloutTargets,omitempty"`
+}
+
+// WorkloadRef names a workload in the namespace of the import.
+type WorkloadRef struct {
+	// Kind is Deployment or StatefulSet
+	Kind string `json:"kind"`
+	// Name is the name of the workload
+	Name string `json:"name"`
+}
+
+// KeystoreOutput configures a keystore added to the target secret.
+type KeystoreOutput struct {
+	// Key is the key of the keystore in the target secret
+	Key string `json:"key,omitempty"`
+	// Alias is the alias of the private key entry; JKS only. Defaults to certificate
+	Alias string `json:"alias,omitempty"`
+	// PasswordSecretRef references the keystore password in a secret in the
+	// import's namespace
+	PasswordSecretRef SecretKeyRef `json:"passwordSecretRef"`
+}
+
+// SecretKeyRef selects a key of a secret in the same namespace.
+type SecretKeyRef struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+	// Key is the key in the secret. Defaults to password
+	Key string `json:"key,omitempty"`
 }
 
 type CertificateImportStatus struct {
 	// LastSyncTime records the most recent successful sync time
-	LastSyncTime *metav1.Time `json:\"lastSyncTime,omitempty\"`
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// SyncCount is the number of successful syncs
+	SyncCount int64 `json:"syncCount,omitempty"`
+	// ObservedGeneration is the metadata.generation acted upon by the most
+	// recent successful sync
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// NotBefore is the start of the validity period of the leaf certificate
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// NotAfter is the expiry of the leaf certificate
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// NextSyncTime is when the next scheduled sync runs, cleared while suspended
+	NextSyncTime *metav1.Time `json:"nextSyncTime,omitempty"`
+	// RetryBackoff is the delay before the next retry of a failed sync, empty
+	// after a successful sync
+	RetryBackoff string `json:"retryBackoff,omitempty"`
+	// LastSyncNow is the value of the cert-trust.flolive.io/sync-now
+	// annotation of the most recent sync it requested
+	LastSyncNow string `json:"lastSyncNow,omitempty"`
+	// Conditions describe the current state of the import, e.g. Conflict
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
 }
 
 // +kubebuilder:object:root=true
 type CertificateImportList struct {
-	metav1.TypeMeta `json:\",inline\"`
-	metav1.ListMeta `json:\"metadata,omitempty\"`
-	Items           []CertificateImport `json:\"items\"`
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateImport `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:resource:scope=Cluster,shortName=ccex
+// +kubebuilder:printcolumn:name=Namespace,JSONPath=.spec.sourceNamespace,description=Source namespace,type=string
+// +kubebuilder:printcolumn:name=Secret,JSONPath=.spec.secretRef,description=Source TLS secret,type=string
+// ClusterCertificateExport specifies a source secret that imports in any
+// namespace can reference as cluster/<name>.
+type ClusterCertificateExport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ClusterCertificateExportSpec   `json:"spec,omitempty"`
+	Status ClusterCertificateExportStatus `json:"status,omitempty"`
+}
+
+type ClusterCertificateExportSpec struct {
+	// SourceNamespace is the namespace of the source secret
+	SourceNamespace string `json:"sourceNamespace"`
+	// SecretRef is the name of a TLS or dockerconfigjson secret in
+	// SourceNamespace
+	SecretRef string `json:"secretRef"`
+	// AllowOpaque also accepts an Opaque source secret. Defaults to false
+	AllowOpaque bool `json:"allowOpaque,omitempty"`
+	// Schedule is a cron expression determining when ClusterTrustBundle is
+	// refreshed. Defaults to @every 1h
+	Schedule string `json:"schedule,omitempty"`
+	// Timezone is an IANA zone Schedule is evaluated in
+	Timezone string `json:"timezone,omitempty"`
+	// ClusterTrustBundle publishes the ca.crt of the source secret as a
+	// ClusterTrustBundle. Requires --enable-cluster-trust-bundles
+	ClusterTrustBundle *ClusterTrustBundleTarget `json:"clusterTrustBundle,omitempty"`
+}
+
+// ClusterTrustBundleTarget names the ClusterTrustBundle a
+// ClusterCertificateExport publishes.
+type ClusterTrustBundleTarget struct {
+	// Name of the ClusterTrustBundle
+	Name string `json:"name"`
+	// SignerName links the bundle to a signer; Name must then be prefixed
+	// with the signer name, "/" replaced by ":"
+	SignerName string `json:"signerName,omitempty"`
+}
+
+type ClusterCertificateExportStatus struct {
+	// LastSyncTime records the most recent successful publish of ClusterTrustBundle
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// SyncCount is the number of successful publishes
+	SyncCount int64 `json:"syncCount,omitempty"`
+	// ObservedGeneration is the metadata.generation acted upon by the most
+	// recent successful publish
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Importers lists the namespace/name of every CertificateImport
+	// referencing the export, sorted
+	Importers []string `json:"importers,omitempty"`
+	// Conditions describe the current state of the export, e.g. Conflict
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type ClusterCertificateExportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ClusterCertificateExport `json:"items"`
 }
@@ -0,0 +1,221 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v2
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TargetKind is the kind of object an import writes.
+type TargetKind string
+
+const (
+	// TargetKindSecret copies the source secret into a secret
+	TargetKindSecret TargetKind = "Secret"
+	// TargetKindConfigMap bundles the CAs of the referenced exports into a
+	// configmap
+	TargetKindConfigMap TargetKind = "ConfigMap"
+)
+
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:resource:scope=Namespaced,shortName=cimp
+// +kubebuilder:printcolumn:name=From,JSONPath=.spec.source.export,description=Source export,type=string
+// +kubebuilder:printcolumn:name=Kind,JSONPath=.spec.target.kind,description=Target kind,type=string
+// +kubebuilder:printcolumn:name=Target,JSONPath=.spec.target.name,description=Target object,type=string
+// +kubebuilder:printcolumn:name=Schedule,JSONPath=.spec.schedule,description=Cron schedule,type=string
+// +kubebuilder:printcolumn:name=Expiry,JSONPath=.status.notAfter,description=Leaf certificate expiry,type=date
+// +kubebuilder:printcolumn:name=Next,JSONPath=.status.nextSyncTime,description=Next scheduled sync,type=date
+// +kubebuilder:printcolumn:name=Suspended,JSONPath=.spec.suspend,description=Whether syncing is paused,type=boolean
+// CertificateImport references CertificateExports and manages a target secret
+// or configmap in this namespace.
+type CertificateImport struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CertificateImportSpec   `json:"spec,omitempty"`
+	Status CertificateImportStatus `json:"status,omitempty"`
+}
+
+type CertificateImportSpec struct {
+	// Source selects the exports the data is copied from
+	Source ImportSource `json:"source,omitempty"`
+	// Target is the object written in this namespace
+	Target TargetRef `json:"target"`
+	// Keys selects, renames and rewrites the copied keys of a secret target
+	Keys KeySelector `json:"keys,omitempty"`
+	// Keystores are added to a secret target next to the copied keys
+	Keystores Keystores `json:"keystores,omitempty"`
+	// Schedule is a cron expression determining when to refresh data from the source
+	Schedule string `json:"schedule,omitempty"`
+	// Timezone is an IANA zone (e.g. America/New_York) Schedule is evaluated in.
+	// Defaults to the controller's local time
+	Timezone string `json:"timezone,omitempty"`
+	// Jitter delays each scheduled sync by a stable amount up to this duration
+	// (e.g. "5m"), derived from the import's UID. Overrides --sync-jitter
+	Jitter *metav1.Duration `json:"jitter,omitempty"`
+	// SyncOnStart syncs the import once when the controller starts, or when
+	// the import is created later, in addition to its schedule. Defaults to
+	// the --immediate-sync-on-start flag
+	SyncOnStart *bool `json:"syncOnStart,omitempty"`
+	// Suspend pauses syncing without deleting the import
+	Suspend bool `json:"suspend,omitempty"`
+	// RolloutTargets are restarted whenever a sync changes the data of an
+	// existing target secret
+	RolloutTargets []WorkloadRef `json:"rolloutTargets,omitempty"`
+}
+
+// ImportSource selects the exports an import copies from, in the format
+// namespace/name or just name (same namespace), or cluster/name to reference
+// a ClusterCertificateExport.
+type ImportSource struct {
+	// Export is the export whose secret is copied into a secret target.
+	// Defaults to the controller's --default-from-export
+	Export string `json:"export,omitempty"`
+	// CABundleExports lists additional exports whose ca.crt is bundled into
+	// a configmap target, or merged into the ca.crt of a secret target
+	CABundleExports []string `json:"caBundleExports,omitempty"`
+	// ExpectedFingerprint is the hex SHA-256 of the DER of the source leaf
+	// certificate. When set, a source with another leaf is not mirrored.
+	// Colons and case are ignored
+	ExpectedFingerprint string `json:"expectedFingerprint,omitempty"`
+	// WaitForValid skips the sync, and retries it, while the source
+	// certificate is empty, unparseable, not yet valid or expired
+	WaitForValid bool `json:"waitForValid,omitempty"`
+	// WaitForData skips the sync, and retries it, while the source secret
+	// has no data yet, e.g. a placeholder of a SealedSecret or
+	// ExternalSecret: an empty tls.crt or tls.key for a TLS source
+	WaitForData bool `json:"waitForData,omitempty"`
+	// VerifyKeyPair checks that tls.crt and tls.key form a valid pair before
+	// copying them. Defaults to true
+	VerifyKeyPair *bool `json:"verifyKeyPair,omitempty"`
+}
+
+// TargetRef names the object an import writes in its namespace.
+type TargetRef struct {
+	// Kind is Secret or ConfigMap
+	Kind TargetKind `json:"kind"`
+	// Name is the name of the target object
+	Name string `json:"name"`
+	// Key is the key of the CA bundle in a configmap target. Defaults to
+	// ca-bundle.crt
+	Key string `json:"key,omitempty"`
+	// Type is the type of a secret target. When empty it is inferred from
+	// the copied data: kubernetes.io/tls when it has tls.crt and tls.key,
+	// kubernetes.io/dockerconfigjson when it has .dockerconfigjson, or Opaque
+	// otherwise.
+	Type corev1.SecretType `json:"type,omitempty"`
+	// Labels are merged into the labels of the target
+	Labels map[string]string `json:"labels,omitempty"`
+	// Annotations are merged into the annotations of the target
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Immutable marks a secret target immutable. Its data is then changed
+	// by deleting and recreating the secret
+	Immutable bool `json:"immutable,omitempty"`
+}
+
+// KeySelector selects and transforms the keys copied from the source secret.
+type KeySelector struct {
+	// Include, when set, limits the copied data to these source keys
+	Include []string `json:"include,omitempty"`
+	// Exclude lists source keys to skip; ignored when Include is set
+	Exclude []string `json:"exclude,omitempty"`
+	// Rename maps source keys to target keys (e.g. tls.crt: cert.pem). Keys
+	// not listed keep their name. Applied after Include/Exclude; every listed
+	// key must be copied. Target keys may be Go templates using .ImportName,
+	// .Namespace and .SourceKey
+	Rename map[string]string `json:"rename,omitempty"`
+	// NormalizePEM re-encodes PEM values of the copied keys with LF line
+	// endings and a single trailing newline. Non-PEM values are copied as is
+	NormalizePEM bool `json:"normalizePEM,omitempty"`
+	// SplitCABundle, when set, writes each certificate of the source ca.crt
+	// to its own key, ca-0.crt, ca-1.crt, ... in bundle order, in addition
+	// to the copied keys
+	SplitCABundle *CABundleSplit `json:"splitCABundle,omitempty"`
+}
+
+// CABundleSplit configures splitting the source CA bundle.
+type CABundleSplit struct {
+	// CAOnly skips certificates that are not CA certificates
+	CAOnly bool `json:"caOnly,omitempty"`
+}
+
+// Keystores configures the keystores added to a secret target.
+type Keystores struct {
+	// PKCS12 adds a PKCS#12 keystore built from tls.crt, tls.key and ca.crt
+	PKCS12 *KeystoreOutput `json:"pkcs12,omitempty"`
+	// JKS adds a Java KeyStore built from tls.crt, tls.key and ca.crt
+	JKS *KeystoreOutput `json:"jks,omitempty"`
+}
+
+// WorkloadRef names a workload in the namespace of the import.
+type WorkloadRef struct {
+	// Kind is Deployment or StatefulSet
+	Kind string `json:"kind"`
+	// Name is the name of the workload
+	Name string `json:"name"`
+}
+
+// KeystoreOutput configures a keystore added to the target secret.
+type KeystoreOutput struct {
+	// Key is the key of the keystore in the target secret
+	Key string `json:"key,omitempty"`
+	// Alias is the alias of the private key entry; JKS only. Defaults to certificate
+	Alias string `json:"alias,omitempty"`
+	// PasswordSecretRef references the keystore password in a secret in the
+	// import's namespace
+	PasswordSecretRef SecretKeyRef `json:"passwordSecretRef"`
+}
+
+// SecretKeyRef selects a key of a secret in the same namespace.
+type SecretKeyRef struct {
+	// Name is the name of the secret
+	Name string `json:"name"`
+	// Key is the key in the secret. Defaults to password
+	Key string `json:"key,omitempty"`
+}
+
+type CertificateImportStatus struct {
+	// LastSyncTime records the most recent successful sync time
+	LastSyncTime *metav1.Time `json:"lastSyncTime,omitempty"`
+	// SyncCount is the number of successful syncs
+	SyncCount int64 `json:"syncCount,omitempty"`
+	// ObservedGeneration is the metadata.generation acted upon by the most
+	// recent successful sync
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// NotBefore is the start of the validity period of the leaf certificate
+	NotBefore *metav1.Time `json:"notBefore,omitempty"`
+	// NotAfter is the expiry of the leaf certificate
+	NotAfter *metav1.Time `json:"notAfter,omitempty"`
+	// NextSyncTime is when the next scheduled sync runs, cleared while suspended
+	NextSyncTime *metav1.Time `json:"nextSyncTime,omitempty"`
+	// RetryBackoff is the delay before the next retry of a failed sync, empty
+	// after a successful sync
+	RetryBackoff string `json:"retryBackoff,omitempty"`
+	// LastSyncNow is the value of the cert-trust.flolive.io/sync-now
+	// annotation of the most recent sync it requested
+	LastSyncNow string `json:"lastSyncNow,omitempty"`
+	// Conditions describe the current state of the import, e.g. Conflict
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+type CertificateImportList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CertificateImport `json:"items"`
+}
@@ -17,22 +17,25 @@ package main
 import (
 	"context"
 	"flag"
+	"fmt"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/go-logr/logr"
 	"github.com/go-logr/zapr"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
-	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
-	"k8s.io/apimachinery/pkg/runtime/schema"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/healthz"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 	metricserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	"github.com/nazman/cert-trust/controllers"
 )
@@ -44,50 +47,291 @@ var (
 
 func init() {
 	_ = clientgoscheme.AddToScheme(scheme)
+}
+
+// setupScheme registers the CRD types under apiGroup. It runs after flag
+// parsing, since the group is configurable.
+func setupScheme(apiGroup string) error {
+	if err := controllers.SetAPIGroup(apiGroup); err != nil {
+		return err
+	}
+	return controllers.AddToScheme(scheme)
+}
+
+// logLevel is a flag.Value for the zap log level. It accepts a level name
+// (debug, info, warn, error) or a non-negative verbosity n, which enables
+// logr's V(n) messages. 1 logs each sync, 2 also logs per-entry details.
+type logLevel struct {
+	zapcore.Level
+}
+
+func (l *logLevel) String() string {
+	if l.Level < zapcore.DebugLevel {
+		return strconv.Itoa(-int(l.Level))
+	}
+	return l.Level.String()
+}
 
-	// Register CRD GVKs for unstructured client
-	schemeBuilder := runtime.NewSchemeBuilder(func(s *runtime.Scheme) error {
-		s.AddKnownTypes(schema.GroupVersion{Group: "cert.trust.flolive.io", Version: "v1"},
-			&metav1.PartialObjectMetadata{},
-			&metav1.PartialObjectMetadataList{},
-		)
-		metav1.AddToGroupVersion(s, schema.GroupVersion{Group: "cert.trust.flolive.io", Version: "v1"})
+func (l *logLevel) Set(s string) error {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < 0 {
+			return fmt.Errorf("verbosity must not be negative, got %d", n)
+		}
+		l.Level = zapcore.Level(-n)
 		return nil
-	})
-	_ = schemeBuilder.AddToScheme(scheme)
+	}
+	return l.Level.Set(s)
+}
+
+// logFormat is a flag.Value for the log encoding, json or console.
+type logFormat string
+
+func (f *logFormat) String() string {
+	if *f == "" {
+		return "json"
+	}
+	return string(*f)
+}
+
+func (f *logFormat) Set(s string) error {
+	switch s {
+	case "json", "console":
+		*f = logFormat(s)
+		return nil
+	}
+	return fmt.Errorf("must be json or console, got %q", s)
 }
 
-func newZapLogger() logr.Logger {
+// newZapConfig returns the production config with level, the encoding of
+// format and ISO8601 timestamps.
+func newZapConfig(level zapcore.Level, format logFormat) zap.Config {
 	cfg := zap.NewProductionConfig()
+	cfg.Level = zap.NewAtomicLevelAt(level)
+	if format == "console" {
+		// human-readable output for running the controller locally
+		cfg.Encoding = "console"
+		cfg.EncoderConfig.EncodeLevel = zapcore.CapitalLevelEncoder
+	}
 	cfg.EncoderConfig.TimeKey = "timestamp"
 	cfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
-	z, _ := cfg.Build()
+	return cfg
+}
+
+func newZapLogger(level zapcore.Level, format logFormat) logr.Logger {
+	z, _ := newZapConfig(level, format).Build()
 	return zapr.NewLogger(z)
 }
 
+// splitList splits a comma-separated flag value, dropping empty entries.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// leaderElection holds the leader election flags.
+type leaderElection struct {
+	enabled       bool
+	namespace     string
+	leaseDuration time.Duration
+	renewDeadline time.Duration
+	retryPeriod   time.Duration
+}
+
+func (l *leaderElection) addFlags(fs *flag.FlagSet) {
+	fs.BoolVar(&l.enabled, "leader-elect", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
+	fs.DurationVar(&l.leaseDuration, "leader-election-lease-duration", 15*time.Second, "How long non-leaders wait before taking over an unrenewed leader lease.")
+	fs.DurationVar(&l.renewDeadline, "leader-election-renew-deadline", 10*time.Second, "How long the leader retries renewing its lease before giving up leadership. Must be less than --leader-election-lease-duration.")
+	fs.DurationVar(&l.retryPeriod, "leader-election-retry-period", 2*time.Second, "How long candidates wait between attempts to acquire or renew the lease. Must be less than --leader-election-renew-deadline.")
+	fs.StringVar(&l.namespace, "leader-election-namespace", "", "Namespace of the leader election lease. Defaults to the namespace the controller runs in.")
+}
+
+// validate checks that 0 < retry period < renew deadline < lease duration.
+func (l *leaderElection) validate() error {
+	if l.retryPeriod <= 0 || l.renewDeadline <= l.retryPeriod || l.leaseDuration <= l.renewDeadline {
+		return fmt.Errorf("leader election periods must satisfy 0 < retry period < renew deadline < lease duration, got --leader-election-retry-period=%s --leader-election-renew-deadline=%s --leader-election-lease-duration=%s", l.retryPeriod, l.renewDeadline, l.leaseDuration)
+	}
+	return nil
+}
+
+// apply sets the leader election options of the manager.
+func (l *leaderElection) apply(o *ctrl.Options) {
+	o.LeaderElection = l.enabled
+	o.LeaderElectionNamespace = l.namespace
+	o.LeaseDuration = &l.leaseDuration
+	o.RenewDeadline = &l.renewDeadline
+	o.RetryPeriod = &l.retryPeriod
+}
+
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "sync-import" {
+		os.Exit(runSyncImport(os.Args[2:]))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "schedules" {
+		os.Exit(runSchedules(os.Args[2:]))
+	}
+
+	var apiGroup string
+	var level logLevel
+	var format logFormat
 	var metricsAddr string
 	var probeAddr string
-	var enableLeaderElection bool
+	var leader leaderElection
 	var immediateOnStart bool
+	var syncOnSecretChange bool
+	var expiryWarningThreshold time.Duration
+	var syncJitter time.Duration
+	var defaultSchedule string
+	var defaultFromExport string
+	var rescheduleInterval time.Duration
+	var cacheSyncPeriod time.Duration
+	var shutdownTimeout time.Duration
+	var dryRun bool
+	var clusterTrustBundles bool
+	var maxConcurrentSyncs int
+	var maxSecretSize int
+	var writeQPS float64
+	var writeBurst int
+	var tracing bool
+	var watchNamespaces string
+	var excludeNamespaces string
+	var namespace string
+	var enableWebhooks bool
+	var webhookPort int
+	var webhookCertDir string
+	var once bool
+	var gcOrphans bool
+	var debugAddr string
 
+	flag.StringVar(&apiGroup, "api-group", controllers.DefaultAPIGroup, "API group the CertificateImport and CertificateExport CRDs are installed under.")
+	flag.Var(&level, "log-level", "Log level: debug, info, warn, error, or a verbosity n enabling more detailed messages (1 logs every sync, 2 also per-entry details).")
+	flag.Var(&level, "zap-log-level", "Alias of --log-level.")
+	flag.Var(&level, "v", "Shorthand for --log-level.")
+	flag.Var(&format, "log-format", "Log encoding: json, or console for human-readable logs.")
 	flag.StringVar(&metricsAddr, "metrics-bind-address", ":8080", "The address the metric endpoint binds to.")
 	flag.StringVar(&probeAddr, "health-probe-bind-address", ":8081", "The address the probe endpoint binds to.")
-	flag.BoolVar(&enableLeaderElection, "leader-elect", false, "Enable leader election for controller manager. Enabling this will ensure there is only one active controller manager.")
-	flag.BoolVar(&immediateOnStart, "immediate-sync-on-start", false, "Trigger a one-time immediate sync when the scheduler starts.")
+	flag.StringVar(&debugAddr, "debug-bind-address", "", "The address the read-only debug endpoints bind to, e.g. localhost:8082. Empty disables them.")
+	leader.addFlags(flag.CommandLine)
+	flag.BoolVar(&immediateOnStart, "immediate-sync-on-start", false, "Trigger an immediate sync of each import when it is first seen, at startup or when created later. Imports can override it with spec.syncOnStart.")
+	flag.BoolVar(&syncOnSecretChange, "sync-on-secret-change", false, "Sync the imports of a source secret as soon as its data changes, in addition to their schedule.")
+	flag.DurationVar(&expiryWarningThreshold, "expiry-warning-threshold", 720*time.Hour, "Flag imports as ExpiringSoon when the certificate expires within this duration. 0 disables the warning.")
+	flag.StringVar(&defaultSchedule, "default-schedule", controllers.DefaultSchedule, "Cron schedule of imports and exports that do not set spec.schedule.")
+	flag.StringVar(&defaultFromExport, "default-from-export", "", "Export (<namespace>/<name> or cluster/<name>) of imports with spec.targetSecret that do not set spec.fromExport. It must exist at startup. Empty disables the default.")
+	flag.DurationVar(&syncJitter, "sync-jitter", 0, "Delay each scheduled import sync by a stable per-import amount up to this duration. Imports can override it with spec.jitter.")
+	flag.DurationVar(&rescheduleInterval, "reschedule-interval", time.Minute, "How often schedules are rebuilt from the current CertificateImports and CertificateExports.")
+	flag.DurationVar(&cacheSyncPeriod, "cache-sync-period", time.Minute, "Minimum frequency at which watched resources are resynced by the manager cache.")
+	flag.DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "How long shutdown waits for running syncs to finish before abandoning them.")
+	flag.StringVar(&watchNamespaces, "watch-namespaces", "", "Comma-separated namespaces to process imports and exports in and write secrets to. Empty means all namespaces.")
+	flag.StringVar(&namespace, "namespace", "", "Confine the controller to this single namespace, for installs with namespaced RBAC. Cross-namespace export references and cluster exports are refused. Mutually exclusive with --watch-namespaces.")
+	flag.StringVar(&excludeNamespaces, "exclude-namespaces", "", "Comma-separated namespaces to never process or write to, even if listed in --watch-namespaces.")
+	flag.IntVar(&maxConcurrentSyncs, "max-concurrent-syncs", 0, "Maximum number of syncs running at once; further syncs wait for a free slot. 0 means no limit.")
+	flag.IntVar(&maxSecretSize, "max-secret-size", 1000000, "Largest total size in bytes of the data of a target secret; larger ones are not written. 0 means no limit.")
+	flag.Float64Var(&writeQPS, "write-qps", 0, "Maximum sustained rate of writes to the cluster per second. 0 means no limit.")
+	flag.IntVar(&writeBurst, "write-burst", 10, "Maximum burst of writes to the cluster above --write-qps.")
+	flag.BoolVar(&tracing, "tracing", false, "Export OpenTelemetry spans of syncs over OTLP/HTTP, configured by the standard OTEL_EXPORTER_OTLP_* environment variables.")
+	flag.BoolVar(&gcOrphans, "gc-orphans", false, "Delete target secrets whose managed-by annotation names an import that no longer exists, e.g. one deleted while the controller was down, and pushed secrets whose export no longer exists.")
+	flag.BoolVar(&dryRun, "dry-run", false, "Log the changes each sync would make, without writing anything to the cluster.")
+	flag.BoolVar(&clusterTrustBundles, "enable-cluster-trust-bundles", false, "Publish the CA of ClusterCertificateExports that set spec.clusterTrustBundle as ClusterTrustBundles. Ignored when the cluster does not serve certificates.k8s.io/v1alpha1.")
+	flag.BoolVar(&enableWebhooks, "enable-webhooks", false, "Serve the validating and defaulting admission webhooks for CertificateImport and CertificateExport, and the conversion webhook between CertificateImport versions.")
+	flag.IntVar(&webhookPort, "webhook-port", 9443, "The port the webhook server listens on.")
+	flag.StringVar(&webhookCertDir, "webhook-cert-dir", "", "Directory containing tls.crt and tls.key for the webhook server. Defaults to <tmp>/k8s-webhook-server/serving-certs.")
+	flag.BoolVar(&once, "once", false, "Sync every import and export a single time and exit, instead of running the controller, e.g. in a Job. Exits non-zero if any sync failed.")
 	flag.Parse()
 
-	setupLog = newZapLogger()
+	setupLog = newZapLogger(level.Level, format)
 	log.SetLogger(setupLog)
 
-	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), ctrl.Options{
-		Scheme:                 scheme,
-		Metrics:                metricserver.Options{BindAddress: metricsAddr},
-		HealthProbeBindAddress: probeAddr,
-		LeaderElection:         enableLeaderElection,
-		LeaderElectionID:       "cert-trust.flolive.io",
-		Cache:                  cache.Options{SyncPeriod: func() *time.Duration { d := time.Minute; return &d }()},
-	})
+	if err := setupScheme(apiGroup); err != nil {
+		setupLog.Error(err, "invalid --api-group")
+		os.Exit(1)
+	}
+	if err := controllers.SetDefaultSchedule(defaultSchedule); err != nil {
+		setupLog.Error(err, "invalid --default-schedule")
+		os.Exit(1)
+	}
+	if err := controllers.SetDefaultFromExport(defaultFromExport); err != nil {
+		setupLog.Error(err, "invalid --default-from-export")
+		os.Exit(1)
+	}
+	if rescheduleInterval <= 0 {
+		setupLog.Error(fmt.Errorf("must be positive, got %s", rescheduleInterval), "invalid --reschedule-interval")
+		os.Exit(1)
+	}
+	if maxConcurrentSyncs < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %d", maxConcurrentSyncs), "invalid --max-concurrent-syncs")
+		os.Exit(1)
+	}
+	if maxSecretSize < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %d", maxSecretSize), "invalid --max-secret-size")
+		os.Exit(1)
+	}
+	if writeQPS < 0 || writeBurst < 1 {
+		setupLog.Error(fmt.Errorf("got --write-qps=%v --write-burst=%d", writeQPS, writeBurst), "--write-qps must not be negative and --write-burst must be positive")
+		os.Exit(1)
+	}
+	if cacheSyncPeriod <= 0 {
+		setupLog.Error(fmt.Errorf("must be positive, got %s", cacheSyncPeriod), "invalid --cache-sync-period")
+		os.Exit(1)
+	}
+	if shutdownTimeout < 0 {
+		setupLog.Error(fmt.Errorf("must not be negative, got %s", shutdownTimeout), "invalid --shutdown-timeout")
+		os.Exit(1)
+	}
+	if namespace != "" && (watchNamespaces != "" || clusterTrustBundles || enableWebhooks) {
+		setupLog.Error(fmt.Errorf("got --namespace=%s", namespace), "--namespace can't be combined with --watch-namespaces, --enable-cluster-trust-bundles or --enable-webhooks")
+		os.Exit(1)
+	}
+	cacheOpts := cache.Options{SyncPeriod: &cacheSyncPeriod}
+	if namespace != "" {
+		cacheOpts.DefaultNamespaces = map[string]cache.Config{namespace: {}}
+	}
+	if err := leader.validate(); err != nil {
+		setupLog.Error(err, "invalid leader election flags")
+		os.Exit(1)
+	}
+
+	opts := controllers.Options{
+		ImmediateOnStart:       immediateOnStart,
+		SyncOnSecretChange:     syncOnSecretChange,
+		ExpiryWarningThreshold: expiryWarningThreshold,
+		SyncJitter:             syncJitter,
+		RescheduleInterval:     rescheduleInterval,
+		WatchNamespaces:        splitList(watchNamespaces),
+		ExcludeNamespaces:      splitList(excludeNamespaces),
+		Namespace:              namespace,
+		DryRun:                 dryRun,
+		ClusterTrustBundles:    clusterTrustBundles,
+		MaxConcurrentSyncs:     maxConcurrentSyncs,
+		WriteQPS:               writeQPS,
+		WriteBurst:             writeBurst,
+		ShutdownTimeout:        shutdownTimeout,
+		MaxSecretSize:          maxSecretSize,
+		GCOrphans:              gcOrphans,
+		DebugBindAddress:       debugAddr,
+	}
+	if once {
+		os.Exit(runOnce(opts))
+	}
+
+	// The manager waits a little longer than the controller drains its syncs,
+	// so the controller gets to log the syncs it abandons
+	gracefulShutdownTimeout := shutdownTimeout + 5*time.Second
+	mgrOpts := ctrl.Options{
+		Scheme:                  scheme,
+		Metrics:                 metricserver.Options{BindAddress: metricsAddr},
+		HealthProbeBindAddress:  probeAddr,
+		LeaderElectionID:        "cert-trust.flolive.io",
+		Cache:                   cacheOpts,
+		Client:                  client.Options{Cache: &client.CacheOptions{Unstructured: true}},
+		WebhookServer:           webhook.NewServer(webhook.Options{Port: webhookPort, CertDir: webhookCertDir}),
+		GracefulShutdownTimeout: &gracefulShutdownTimeout,
+	}
+	leader.apply(&mgrOpts)
+	mgr, err := ctrl.NewManager(ctrl.GetConfigOrDie(), mgrOpts)
 	if err != nil {
 		setupLog.Error(err, "unable to start manager")
 		os.Exit(1)
@@ -102,13 +346,35 @@ func main() {
 		os.Exit(1)
 	}
 
-	if err := controllers.RegisterWithManager(mgr, immediateOnStart); err != nil {
+	if err := controllers.CheckDefaultFromExport(context.Background(), mgr.GetAPIReader()); err != nil {
+		setupLog.Error(err, "invalid --default-from-export")
+		os.Exit(1)
+	}
+
+	if err := controllers.RegisterWithManager(mgr, opts); err != nil {
 		setupLog.Error(err, "unable to register controllers")
 		os.Exit(1)
 	}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+	if dryRun {
+		setupLog.Info("dry run enabled, no changes will be written")
+	}
+	if enableWebhooks {
+		controllers.RegisterWebhooksWithManager(mgr)
+	}
+
+	// Cancelled on SIGTERM or SIGINT, which starts the graceful shutdown
+	ctx := ctrl.SetupSignalHandler()
+
+	if tracing {
+		shutdown, err := setupTracing(ctx)
+		if err != nil {
+			setupLog.Error(err, "unable to set up tracing")
+			os.Exit(1)
+		}
+		defer func() { _ = shutdown(context.Background()) }()
+		setupLog.Info("tracing enabled, exporting spans over OTLP")
+	}
 
 	if err := mgr.Start(ctx); err != nil {
 		setupLog.Error(err, "problem running manager")
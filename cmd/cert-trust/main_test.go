@@ -0,0 +1,203 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-logr/zapr"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	ctrl "sigs.k8s.io/controller-runtime"
+)
+
+func TestLeaderElectionFlags(t *testing.T) {
+	tests := []struct {
+		name      string
+		args      []string
+		want      leaderElection
+		wantError string
+	}{
+		{
+			name: "defaults",
+			want: leaderElection{leaseDuration: 15 * time.Second, renewDeadline: 10 * time.Second, retryPeriod: 2 * time.Second},
+		},
+		{
+			name: "all set",
+			args: []string{"--leader-elect", "--leader-election-namespace=cert-trust", "--leader-election-lease-duration=60s", "--leader-election-renew-deadline=40s", "--leader-election-retry-period=5s"},
+			want: leaderElection{enabled: true, namespace: "cert-trust", leaseDuration: time.Minute, renewDeadline: 40 * time.Second, retryPeriod: 5 * time.Second},
+		},
+		{
+			name:      "renew deadline not below the lease",
+			args:      []string{"--leader-election-lease-duration=10s"},
+			wantError: "0 < retry period < renew deadline < lease duration",
+		},
+		{
+			name:      "retry period not below the renew deadline",
+			args:      []string{"--leader-election-retry-period=10s"},
+			wantError: "0 < retry period < renew deadline < lease duration",
+		},
+		{
+			name:      "zero retry period",
+			args:      []string{"--leader-election-retry-period=0s"},
+			wantError: "0 < retry period < renew deadline < lease duration",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fs := flag.NewFlagSet("cert-trust", flag.ContinueOnError)
+			fs.SetOutput(io.Discard)
+			var got leaderElection
+			got.addFlags(fs)
+			if err := fs.Parse(tt.args); err != nil {
+				t.Fatal(err)
+			}
+			err := got.validate()
+			if tt.wantError != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantError) {
+					t.Errorf("got error %v, want it to contain %q", err, tt.wantError)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Fatalf("got %+v, want %+v", got, tt.want)
+			}
+			var opts ctrl.Options
+			got.apply(&opts)
+			if opts.LeaderElection != tt.want.enabled || opts.LeaderElectionNamespace != tt.want.namespace ||
+				*opts.LeaseDuration != tt.want.leaseDuration || *opts.RenewDeadline != tt.want.renewDeadline || *opts.RetryPeriod != tt.want.retryPeriod {
+				t.Errorf("got manager options %v/%q/%s/%s/%s, want %+v", opts.LeaderElection, opts.LeaderElectionNamespace, *opts.LeaseDuration, *opts.RenewDeadline, *opts.RetryPeriod, tt.want)
+			}
+		})
+	}
+}
+
+func TestLogLevel(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    zapcore.Level
+		wantErr bool
+	}{
+		{value: "info", want: zapcore.InfoLevel},
+		{value: "error", want: zapcore.ErrorLevel},
+		{value: "debug", want: zapcore.DebugLevel},
+		{value: "0", want: zapcore.InfoLevel},
+		{value: "2", want: zapcore.Level(-2)},
+		{value: "-1", wantErr: true},
+		{value: "loud", wantErr: true},
+	}
+	for _, tt := range tests {
+		var l logLevel
+		err := l.Set(tt.value)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("Set(%q) succeeded, want an error", tt.value)
+			}
+			continue
+		}
+		if err != nil || l.Level != tt.want {
+			t.Errorf("Set(%q) = %v, %v, want %v", tt.value, l.Level, err, tt.want)
+		}
+	}
+}
+
+func TestZapConfigVerbosity(t *testing.T) {
+	tests := []struct {
+		level string
+		want  []string
+	}{
+		{level: "error", want: []string{"failed"}},
+		{level: "info", want: []string{"synced", "failed"}},
+		{level: "1", want: []string{"executing sync", "synced", "failed"}},
+		{level: "2", want: []string{"entry details", "executing sync", "synced", "failed"}},
+	}
+	for _, tt := range tests {
+		var l logLevel
+		if err := l.Set(tt.level); err != nil {
+			t.Fatal(err)
+		}
+		core, logs := observer.New(newZapConfig(l.Level, "").Level)
+		logger := zapr.NewLogger(zap.New(core))
+		logger.V(2).Info("entry details")
+		logger.V(1).Info("executing sync")
+		logger.Info("synced")
+		logger.Error(errors.New("boom"), "failed")
+
+		var got []string
+		for _, entry := range logs.All() {
+			got = append(got, entry.Message)
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("level %s: got messages %q, want %q", tt.level, got, tt.want)
+		}
+	}
+}
+
+func TestLogFormat(t *testing.T) {
+	var f logFormat
+	if got := f.String(); got != "json" {
+		t.Errorf("got default format %q, want json", got)
+	}
+	for _, value := range []string{"json", "console"} {
+		if err := f.Set(value); err != nil || f.String() != value {
+			t.Errorf("Set(%q) = %v, format %q", value, err, f.String())
+		}
+	}
+	if err := f.Set("text"); err == nil {
+		t.Error("Set(\"text\") succeeded, want an error")
+	}
+}
+
+func TestZapConfigEncoding(t *testing.T) {
+	tests := []struct {
+		format logFormat
+		want   *regexp.Regexp
+	}{
+		{format: "", want: regexp.MustCompile(`^\{"level":"info","timestamp":"\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}[+-Z].*"msg":"synced","import":"frontend/app"\}$`)},
+		{format: "json", want: regexp.MustCompile(`^\{"level":"info","timestamp":"\d{4}-\d\d-\d\dT`)},
+		{format: "console", want: regexp.MustCompile(`^\d{4}-\d\d-\d\dT\d\d:\d\d:\d\d\.\d{3}[+-Z]\S*\tINFO\t.*synced\t\{"import": "frontend/app"\}$`)},
+	}
+	for _, tt := range tests {
+		out := filepath.Join(t.TempDir(), "log")
+		cfg := newZapConfig(zapcore.InfoLevel, tt.format)
+		cfg.OutputPaths = []string{out}
+		z, err := cfg.Build()
+		if err != nil {
+			t.Fatal(err)
+		}
+		zapr.NewLogger(z).Info("synced", "import", "frontend/app")
+		_ = z.Sync()
+		got, err := os.ReadFile(out)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if line := strings.TrimSpace(string(got)); !tt.want.MatchString(line) {
+			t.Errorf("format %q: got %q, want it to match %s", tt.format, line, tt.want)
+		}
+	}
+}
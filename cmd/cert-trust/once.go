@@ -0,0 +1,79 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nazman/cert-trust/controllers"
+)
+
+// runOnce implements --once: it syncs every import and export a single time
+// using the ambient kubeconfig and returns the process exit code.
+func runOnce(opts controllers.Options) int {
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to load kubeconfig")
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		return 1
+	}
+	if err := controllers.CheckDefaultFromExport(context.Background(), c); err != nil {
+		setupLog.Error(err, "invalid --default-from-export")
+		return 1
+	}
+	if opts.DryRun {
+		setupLog.Info("dry run enabled, no changes will be written")
+	}
+	results, err := controllers.SyncAll(ctrl.SetupSignalHandler(), c, scheme, opts)
+	if err != nil {
+		setupLog.Error(err, "unable to list imports and exports")
+		return 1
+	}
+	return reportSyncResults(os.Stdout, results)
+}
+
+// reportSyncResults prints the outcome of every sync and a summary to w, and
+// returns 1 if any sync failed, else 0.
+func reportSyncResults(w io.Writer, results []controllers.SyncResult) int {
+	var synced, skipped, failed int
+	for _, r := range results {
+		switch {
+		case r.Err != nil:
+			failed++
+			fmt.Fprintf(w, "%s %s/%s failed: %v\n", r.Kind, r.Namespace, r.Name, r.Err)
+		case r.Skipped:
+			skipped++
+			fmt.Fprintf(w, "%s %s/%s skipped: suspended\n", r.Kind, r.Namespace, r.Name)
+		default:
+			synced++
+			fmt.Fprintf(w, "%s %s/%s synced\n", r.Kind, r.Namespace, r.Name)
+		}
+	}
+	fmt.Fprintf(w, "%d synced, %d skipped, %d failed\n", synced, skipped, failed)
+	if failed > 0 {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,76 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/nazman/cert-trust/controllers"
+)
+
+func TestReportSyncResults(t *testing.T) {
+	synced := controllers.SyncResult{Kind: "CertificateImport", Namespace: "frontend", Name: "app"}
+	skipped := controllers.SyncResult{Kind: "CertificateExport", Namespace: "backend", Name: "paused", Skipped: true}
+	failed := controllers.SyncResult{Kind: "CertificateImport", Namespace: "frontend", Name: "broken", Err: errors.New("export backend/missing not found")}
+	tests := []struct {
+		name     string
+		results  []controllers.SyncResult
+		wantCode int
+		wantOut  string
+	}{
+		{
+			name:     "nothing to sync",
+			wantCode: 0,
+			wantOut:  "0 synced, 0 skipped, 0 failed\n",
+		},
+		{
+			name:     "all synced or skipped",
+			results:  []controllers.SyncResult{synced, skipped},
+			wantCode: 0,
+			wantOut: "CertificateImport frontend/app synced\n" +
+				"CertificateExport backend/paused skipped: suspended\n" +
+				"1 synced, 1 skipped, 0 failed\n",
+		},
+		{
+			name:     "mixed",
+			results:  []controllers.SyncResult{synced, failed, skipped},
+			wantCode: 1,
+			wantOut: "CertificateImport frontend/app synced\n" +
+				"CertificateImport frontend/broken failed: export backend/missing not found\n" +
+				"CertificateExport backend/paused skipped: suspended\n" +
+				"1 synced, 1 skipped, 1 failed\n",
+		},
+		{
+			name:     "all failed",
+			results:  []controllers.SyncResult{failed},
+			wantCode: 1,
+			wantOut: "CertificateImport frontend/broken failed: export backend/missing not found\n" +
+				"0 synced, 0 skipped, 1 failed\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if code := reportSyncResults(&buf, tt.results); code != tt.wantCode {
+				t.Errorf("got exit code %d, want %d", code, tt.wantCode)
+			}
+			if got := buf.String(); got != tt.wantOut {
+				t.Errorf("got output\n%s\nwant\n%s", got, tt.wantOut)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/nazman/cert-trust/controllers"
+)
+
+// runSchedules implements `cert-trust schedules [flags]`: it prints the
+// effective schedule and next runs of every import and export read with the
+// ambient kubeconfig, and returns the process exit code, 1 if any schedule
+// is invalid.
+func runSchedules(args []string) int {
+	fs := flag.NewFlagSet("schedules", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s schedules [flags]\n\nList the effective schedule and next runs of every CertificateImport and CertificateExport.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	apiGroup := fs.String("api-group", controllers.DefaultAPIGroup, "API group the CertificateImport and CertificateExport CRDs are installed under.")
+	defaultSchedule := fs.String("default-schedule", controllers.DefaultSchedule, "Cron schedule of imports and exports that do not set spec.schedule, as passed to the controller.")
+	syncJitter := fs.Duration("sync-jitter", 0, "Default jitter of imports, as passed to the controller.")
+	namespace := fs.String("namespace", "", "Only list imports and exports in this namespace. Empty means all namespaces.")
+	count := fs.Int("count", 3, "Number of upcoming runs to list per schedule.")
+	output := fs.String("output", "table", "Output format: table or json.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 0 || *count < 0 || (*output != "table" && *output != "json") {
+		fs.Usage()
+		return 2
+	}
+	if err := setupScheme(*apiGroup); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --api-group: %v\n", err)
+		return 2
+	}
+	if err := controllers.SetDefaultSchedule(*defaultSchedule); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid --default-schedule: %v\n", err)
+		return 2
+	}
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to load kubeconfig: %v\n", err)
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to create client: %v\n", err)
+		return 1
+	}
+
+	infos, err := controllers.Schedules(context.Background(), c, controllers.Options{SyncJitter: *syncJitter}, *namespace, *count, time.Now())
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to list schedules: %v\n", err)
+		return 1
+	}
+	return printSchedules(os.Stdout, infos, *output)
+}
+
+// printSchedules writes infos to w in output, table or json, and returns the
+// exit code of runSchedules, 1 if any schedule is invalid.
+func printSchedules(w io.Writer, infos []controllers.ScheduleInfo, output string) int {
+	var err error
+	if output == "json" {
+		err = printSchedulesJSON(w, infos)
+	} else {
+		err = printSchedulesTable(w, infos)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "unable to print schedules: %v\n", err)
+		return 1
+	}
+	for _, info := range infos {
+		if !info.Valid {
+			return 1
+		}
+	}
+	return 0
+}
+
+func printSchedulesJSON(w io.Writer, infos []controllers.ScheduleInfo) error {
+	if infos == nil {
+		infos = []controllers.ScheduleInfo{}
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(infos)
+}
+
+func printSchedulesTable(w io.Writer, infos []controllers.ScheduleInfo) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(tw, "KIND\tNAMESPACE\tNAME\tSCHEDULE\tVALID\tSUSPENDED\tNEXT RUNS")
+	for _, info := range infos {
+		next := info.Error
+		if info.Valid {
+			runs := make([]string, len(info.NextRuns))
+			for i, t := range info.NextRuns {
+				runs[i] = t.Format(time.RFC3339)
+			}
+			next = strings.Join(runs, ", ")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%t\t%t\t%s\n", info.Kind, info.Namespace, info.Name, info.Schedule, info.Valid, info.Suspended, next)
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/nazman/cert-trust/controllers"
+)
+
+func TestPrintSchedules(t *testing.T) {
+	valid := controllers.ScheduleInfo{
+		Kind: "CertificateImport", Namespace: "frontend", Name: "app", Schedule: "CRON_TZ=UTC 0 * * * *", Valid: true,
+		NextRuns: []time.Time{time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)},
+	}
+	invalid := controllers.ScheduleInfo{
+		Kind: "CertificateExport", Namespace: "backend", Name: "app", Schedule: "every day", Error: "expected exactly 5 fields, found 2: [every day]",
+	}
+	tests := []struct {
+		name      string
+		infos     []controllers.ScheduleInfo
+		output    string
+		wantCode  int
+		wantLines []string
+	}{
+		{
+			name:     "valid table",
+			infos:    []controllers.ScheduleInfo{valid},
+			output:   "table",
+			wantCode: 0,
+			wantLines: []string{
+				"KIND                NAMESPACE   NAME   SCHEDULE                VALID   SUSPENDED   NEXT RUNS",
+				"CertificateImport   frontend    app    CRON_TZ=UTC 0 * * * *   true    false       2025-06-01T11:00:00Z, 2025-06-01T12:00:00Z",
+			},
+		},
+		{
+			name:     "invalid table",
+			infos:    []controllers.ScheduleInfo{valid, invalid},
+			output:   "table",
+			wantCode: 1,
+			wantLines: []string{
+				"KIND                NAMESPACE   NAME   SCHEDULE                VALID   SUSPENDED   NEXT RUNS",
+				"CertificateImport   frontend    app    CRON_TZ=UTC 0 * * * *   true    false       2025-06-01T11:00:00Z, 2025-06-01T12:00:00Z",
+				"CertificateExport   backend     app    every day               false   false       expected exactly 5 fields, found 2: [every day]",
+			},
+		},
+		{
+			name:      "no schedules",
+			output:    "table",
+			wantCode:  0,
+			wantLines: []string{"KIND   NAMESPACE   NAME   SCHEDULE   VALID   SUSPENDED   NEXT RUNS"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if code := printSchedules(&buf, tt.infos, tt.output); code != tt.wantCode {
+				t.Errorf("got exit code %d, want %d", code, tt.wantCode)
+			}
+			var lines []string
+			for _, line := range strings.Split(strings.TrimRight(buf.String(), "\n"), "\n") {
+				lines = append(lines, strings.TrimRight(line, " "))
+			}
+			if !reflect.DeepEqual(lines, tt.wantLines) {
+				t.Errorf("got\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(tt.wantLines, "\n"))
+			}
+		})
+	}
+}
+
+func TestPrintSchedulesJSON(t *testing.T) {
+	infos := []controllers.ScheduleInfo{
+		{Kind: "CertificateImport", Namespace: "frontend", Name: "app", Schedule: "0 * * * *", Valid: true, NextRuns: []time.Time{time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC)}},
+		{Kind: "CertificateExport", Namespace: "backend", Name: "app", Schedule: "every day", Error: "bad schedule"},
+	}
+	var buf bytes.Buffer
+	if code := printSchedules(&buf, infos, "json"); code != 1 {
+		t.Errorf("got exit code %d with an invalid schedule, want 1", code)
+	}
+	var got []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("invalid JSON %q: %v", buf.String(), err)
+	}
+	if len(got) != 2 || got[0]["valid"] != true || got[0]["nextRuns"].([]interface{})[0] != "2025-06-01T11:00:00Z" ||
+		got[1]["valid"] != false || got[1]["error"] != "bad schedule" {
+		t.Errorf("got %v", got)
+	}
+
+	buf.Reset()
+	if code := printSchedules(&buf, nil, "json"); code != 0 {
+		t.Errorf("got exit code %d without schedules, want 0", code)
+	}
+	if got := strings.TrimSpace(buf.String()); got != "[]" {
+		t.Errorf("got %q without schedules, want an empty list", got)
+	}
+}
+
+func TestRunSchedulesUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "unknown output", args: []string{"--output=yaml"}},
+		{name: "negative count", args: []string{"--count=-1"}},
+		{name: "argument", args: []string{"frontend"}},
+		{name: "invalid default schedule", args: []string{"--default-schedule=every day"}},
+	}
+	for _, tt := range tests {
+		if code := runSchedules(tt.args); code != 2 {
+			t.Errorf("%s: got exit code %d, want 2", tt.name, code)
+		}
+	}
+}
@@ -0,0 +1,116 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/tools/clientcmd"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/nazman/cert-trust/controllers"
+)
+
+// runSyncImport implements `cert-trust sync-import [flags] [namespace/]name`:
+// it syncs one CertificateImport once using the ambient kubeconfig and
+// returns the process exit code.
+func runSyncImport(args []string) int {
+	fs := flag.NewFlagSet("sync-import", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: %s sync-import [flags] [namespace/]name\n\nSync a single CertificateImport once and exit.\n\n", os.Args[0])
+		fs.PrintDefaults()
+	}
+	apiGroup := fs.String("api-group", controllers.DefaultAPIGroup, "API group the CertificateImport and CertificateExport CRDs are installed under.")
+	var level logLevel
+	var format logFormat
+	fs.Var(&level, "log-level", "Log level: debug, info, warn, error, or a verbosity n enabling more detailed messages.")
+	fs.Var(&level, "zap-log-level", "Alias of --log-level.")
+	fs.Var(&level, "v", "Shorthand for --log-level.")
+	fs.Var(&format, "log-format", "Log encoding: json, or console for human-readable logs.")
+	defaultSchedule := fs.String("default-schedule", controllers.DefaultSchedule, "Cron schedule of imports that do not set spec.schedule, used to compute status.nextSyncTime.")
+	defaultFromExport := fs.String("default-from-export", "", "Export of imports with spec.targetSecret that do not set spec.fromExport, as passed to the controller.")
+	dryRun := fs.Bool("dry-run", false, "Log the changes the sync would make, without writing anything to the cluster.")
+	expiryWarningThreshold := fs.Duration("expiry-warning-threshold", 720*time.Hour, "Flag the import as ExpiringSoon when the certificate expires within this duration. 0 disables the warning.")
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() != 1 {
+		fs.Usage()
+		return 2
+	}
+
+	setupLog = newZapLogger(level.Level, format)
+	log.SetLogger(setupLog)
+
+	if err := setupScheme(*apiGroup); err != nil {
+		setupLog.Error(err, "invalid --api-group")
+		return 2
+	}
+	if err := controllers.SetDefaultSchedule(*defaultSchedule); err != nil {
+		setupLog.Error(err, "invalid --default-schedule")
+		return 2
+	}
+	if err := controllers.SetDefaultFromExport(*defaultFromExport); err != nil {
+		setupLog.Error(err, "invalid --default-from-export")
+		return 2
+	}
+	namespace, name, err := importKey(fs.Arg(0))
+	if err != nil {
+		setupLog.Error(err, "invalid import reference", "import", fs.Arg(0))
+		return 2
+	}
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		setupLog.Error(err, "unable to load kubeconfig")
+		return 1
+	}
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		setupLog.Error(err, "unable to create client")
+		return 1
+	}
+
+	opts := controllers.Options{ExpiryWarningThreshold: *expiryWarningThreshold, DryRun: *dryRun}
+	if err := controllers.SyncImport(context.Background(), c, scheme, opts, namespace, name); err != nil {
+		fmt.Fprintf(os.Stderr, "sync of CertificateImport %s/%s failed: %v\n", namespace, name, err)
+		return 1
+	}
+	fmt.Printf("CertificateImport %s/%s synced\n", namespace, name)
+	return 0
+}
+
+// importKey splits namespace/name, defaulting the namespace to the one of
+// the current kubeconfig context.
+func importKey(ref string) (string, string, error) {
+	if ns, name, ok := strings.Cut(ref, "/"); ok {
+		if ns == "" || name == "" {
+			return "", "", fmt.Errorf("expected namespace/name, got %q", ref)
+		}
+		return ns, name, nil
+	}
+	ns, _, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(
+		clientcmd.NewDefaultClientConfigLoadingRules(), &clientcmd.ConfigOverrides{}).Namespace()
+	if err != nil {
+		return "", "", err
+	}
+	return ns, ref, nil
+}
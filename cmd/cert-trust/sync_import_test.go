@@ -0,0 +1,107 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// setKubeconfig points KUBECONFIG at a kubeconfig whose current context
+// uses namespace, or none if empty.
+func setKubeconfig(t *testing.T, namespace string) {
+	t.Helper()
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte(`apiVersion: v1
+kind: Config
+clusters:
+- name: test
+  cluster:
+    server: https://127.0.0.1:6443
+users:
+- name: test
+contexts:
+- name: test
+  context:
+    cluster: test
+    user: test
+    namespace: `+namespace+`
+current-context: test
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("KUBECONFIG", kubeconfig)
+}
+
+func TestImportKey(t *testing.T) {
+	setKubeconfig(t, "frontend")
+
+	tests := []struct {
+		ref           string
+		wantNamespace string
+		wantName      string
+		wantErr       bool
+	}{
+		{ref: "backend/app", wantNamespace: "backend", wantName: "app"},
+		{ref: "app", wantNamespace: "frontend", wantName: "app"},
+		{ref: "/app", wantErr: true},
+		{ref: "backend/", wantErr: true},
+	}
+	for _, tt := range tests {
+		ns, name, err := importKey(tt.ref)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("importKey(%q) = %s/%s, want an error", tt.ref, ns, name)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("importKey(%q): %v", tt.ref, err)
+			continue
+		}
+		if ns != tt.wantNamespace || name != tt.wantName {
+			t.Errorf("importKey(%q) = %s/%s, want %s/%s", tt.ref, ns, name, tt.wantNamespace, tt.wantName)
+		}
+	}
+}
+
+func TestImportKeyDefaultNamespace(t *testing.T) {
+	setKubeconfig(t, "")
+	ns, name, err := importKey("app")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if ns != "default" || name != "app" {
+		t.Errorf("got %s/%s for a context without a namespace, want default/app", ns, name)
+	}
+}
+
+func TestRunSyncImportUsage(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{name: "no import", args: nil},
+		{name: "two imports", args: []string{"a", "b"}},
+		{name: "unknown flag", args: []string{"--bogus", "a"}},
+		{name: "invalid reference", args: []string{"backend/"}},
+	}
+	for _, tt := range tests {
+		if code := runSyncImport(tt.args); code != 2 {
+			t.Errorf("%s: got exit code %d, want 2", tt.name, code)
+		}
+	}
+}
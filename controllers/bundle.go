@@ -0,0 +1,243 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultBundleKey is the ConfigMap key the CA bundle is written to when
+// spec.targetConfigMapKey is unset.
+const defaultBundleKey = "ca-bundle.crt"
+
+// importExportRefs returns every export an import references: spec.fromExport
+// (if set, or the default export of a secret import) followed by
+// spec.fromExports.
+func importExportRefs(imp *unstructured.Unstructured) []string {
+	var refs []string
+	if ref := fromExportOf(imp); ref != "" {
+		refs = append(refs, ref)
+	}
+	return append(refs, getStringSlice(imp.Object, "spec.fromExports")...)
+}
+
+// collectExportCAs returns the ca.crt of the source secret behind each of
+// refs, along with the source secret keys, for merging with buildCABundle.
+func (s *SyncController) collectExportCAs(ctx context.Context, imp *unstructured.Unstructured, refs []string) ([][]byte, []string, error) {
+	logger := log.FromContext(ctx).WithValues("import", fmt.Sprintf("%s/%s", imp.GetNamespace(), imp.GetName()))
+	var sources [][]byte
+	var srcKeys []string
+	for _, ref := range refs {
+		exp, err := getExport(ctx, s, imp.GetNamespace(), ref)
+		if err != nil {
+			logger.Error(err, "failed to get export", "fromExport", ref)
+			return nil, nil, err
+		}
+		if err := s.authorizeImport(ctx, imp, exp); err != nil {
+			logger.Error(err, "refusing to copy from export", "fromExport", ref)
+			return nil, nil, err
+		}
+		srcKey := exportSource(exp)
+		srcKeys = append(srcKeys, srcKey.String())
+		var src corev1.Secret
+		if err := s.getSourceSecret(ctx, srcKey, &src); err != nil {
+			logger.Error(err, "failed to get source secret", "secretRef", srcKey.Name, "namespace", srcKey.Namespace)
+			return nil, nil, err
+		}
+		ca := src.Data["ca.crt"]
+		if len(ca) == 0 {
+			return nil, nil, fmt.Errorf("source secret %s of export %s has no ca.crt", srcKey, ref)
+		}
+		if _, _, err := buildCABundle(ca); errors.Is(err, ErrPrivateKeyInBundle) {
+			err = fmt.Errorf("source secret %s of export %s: %w", srcKey, ref, err)
+			logger.Error(err, "refusing to bundle CA")
+			return nil, nil, err
+		}
+		sources = append(sources, ca)
+	}
+	return sources, srcKeys, nil
+}
+
+// syncBundleImport concatenates the ca.crt of every referenced export into a
+// single PEM bundle and writes it to spec.targetConfigMap.
+func (s *SyncController) syncBundleImport(ctx context.Context, imp *unstructured.Unstructured) error {
+	namespace, name := imp.GetNamespace(), imp.GetName()
+	logger := log.FromContext(ctx).WithValues("import", fmt.Sprintf("%s/%s", namespace, name))
+	targetConfigMap := getString(imp.Object, "spec.targetConfigMap")
+	key := getString(imp.Object, "spec.targetConfigMapKey")
+	if key == "" {
+		key = defaultBundleKey
+	}
+
+	refs := importExportRefs(imp)
+	if len(refs) == 0 {
+		return fmt.Errorf("import %s/%s: targetConfigMap requires fromExport or fromExports", namespace, name)
+	}
+	sources, srcKeys, err := s.collectExportCAs(ctx, imp, refs)
+	if err != nil {
+		return err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("source.secrets", srcKeys))
+	bundle, count, err := buildCABundle(sources...)
+	if err != nil {
+		return fmt.Errorf("import %s/%s: %w", namespace, name, err)
+	}
+
+	owner := fmt.Sprintf("%s/%s", namespace, name)
+	var cm corev1.ConfigMap
+	err = s.liveReader().Get(ctx, types.NamespacedName{Namespace: namespace, Name: targetConfigMap}, &cm)
+	switch {
+	case apierrors.IsNotFound(err):
+		cm = corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        targetConfigMap,
+				Annotations: map[string]string{managedByAnnotation: owner},
+			},
+			Data: map[string]string{key: string(bundle)},
+		}
+		if err := controllerutil.SetControllerReference(imp, &cm, s.scheme); err != nil {
+			return err
+		}
+		if err := s.Create(ctx, &cm); err != nil {
+			logger.Error(err, "failed to create target configmap", "targetConfigMap", targetConfigMap)
+			return err
+		}
+		logger.Info("created target configmap", "targetConfigMap", targetConfigMap, "certificates", count)
+	case err != nil:
+		return err
+	default:
+		if managedBy := cm.Annotations[managedByAnnotation]; managedBy != owner {
+			err := fmt.Errorf("%w: target configmap %s/%s is not managed by import %s (managed-by: %q)", ErrTargetNotManaged, namespace, targetConfigMap, owner, managedBy)
+			logger.Error(err, "refusing to overwrite target configmap")
+			_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+				return setCondition(imp, conditionConflict, metav1.ConditionTrue, reasonTargetNotManaged, err.Error())
+			})
+			return err
+		}
+		if current, ok := cm.Data[key]; ok && current == string(bundle) {
+			logger.V(1).Info("target configmap up to date, skipping update", "targetConfigMap", targetConfigMap)
+			break
+		}
+		if cm.Data == nil {
+			cm.Data = map[string]string{}
+		}
+		cm.Data[key] = string(bundle)
+		if err := s.Update(ctx, &cm); err != nil {
+			logger.Error(err, "failed to update target configmap", "targetConfigMap", targetConfigMap)
+			return err
+		}
+		logger.Info("updated target configmap", "targetConfigMap", targetConfigMap, "certificates", count)
+	}
+
+	// Record the sync in the status of the import (best-effort)
+	_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		markSynced(imp)
+		removeConditionWithReason(imp, conditionConflict, reasonTargetNotManaged)
+		removeCondition(imp, conditionNotAuthorized)
+		if next, err := s.nextSyncTime(imp, time.Now()); err == nil {
+			setNextSyncTime(imp, next)
+		}
+		return true
+	})
+	return nil
+}
+
+// buildCABundle merges the CERTIFICATE blocks of the given PEM inputs into a
+// single bundle. Certificates are deduplicated by the SHA-256 fingerprint of
+// their DER encoding and sorted by it, so the output is stable regardless of
+// input order. It returns the bundle and the number of certificates in it.
+// Inputs carrying a private key are rejected, since the bundle is written to
+// a configmap.
+func buildCABundle(sources ...[]byte) ([]byte, int, error) {
+	byFingerprint := map[string][]byte{}
+	for _, src := range sources {
+		rest := src
+		for {
+			var block *pem.Block
+			block, rest = pem.Decode(rest)
+			if block == nil {
+				break
+			}
+			if strings.HasSuffix(block.Type, "PRIVATE KEY") {
+				return nil, 0, ErrPrivateKeyInBundle
+			}
+			if block.Type != "CERTIFICATE" {
+				continue
+			}
+			sum := sha256.Sum256(block.Bytes)
+			byFingerprint[hex.EncodeToString(sum[:])] = block.Bytes
+		}
+	}
+	if len(byFingerprint) == 0 {
+		return nil, 0, fmt.Errorf("no certificates found in ca.crt of the referenced exports")
+	}
+	fingerprints := make([]string, 0, len(byFingerprint))
+	for fp := range byFingerprint {
+		fingerprints = append(fingerprints, fp)
+	}
+	sort.Strings(fingerprints)
+	var buf bytes.Buffer
+	for _, fp := range fingerprints {
+		_ = pem.Encode(&buf, &pem.Block{Type: "CERTIFICATE", Bytes: byFingerprint[fp]})
+	}
+	return buf.Bytes(), len(fingerprints), nil
+}
+
+// splitCAKey matches the keys written by splitCABundle, so stale ones can be
+// removed from the target when the bundle shrinks.
+var splitCAKey = regexp.MustCompile(`^ca-[0-9]+\.crt$`)
+
+// splitCABundle returns each certificate of the PEM bundle caPEM under its own
+// key ca-0.crt, ca-1.crt, ... in bundle order. With caOnly, certificates that
+// are not CA certificates are skipped and the remaining ones numbered without
+// gaps.
+func splitCABundle(caPEM []byte, caOnly bool) (map[string][]byte, error) {
+	certs, err := parseCertificates(caPEM)
+	if err != nil {
+		return nil, fmt.Errorf("parsing ca.crt: %w", err)
+	}
+	out := map[string][]byte{}
+	for _, crt := range certs {
+		if caOnly && !crt.IsCA {
+			continue
+		}
+		out[fmt.Sprintf("ca-%d.crt", len(out))] = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: crt.Raw})
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("no certificates to split in ca.crt")
+	}
+	return out, nil
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestSyncBundleImportSkipsUnchangedWrite(t *testing.T) {
+	ca, _ := newKeyPair(t, "ca")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "ca-tls", corev1.SecretTypeOpaque, map[string][]byte{"ca.crt": ca}),
+		newExport("backend", "ca", "ca-tls"),
+		newImport("frontend", "bundle", map[string]interface{}{"fromExports": []interface{}{"backend/ca"}, "targetConfigMap": "ca-bundle"}),
+	)
+	ctx := context.Background()
+	getConfigMap := func() *corev1.ConfigMap {
+		var cm corev1.ConfigMap
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "frontend", Name: "ca-bundle"}, &cm); err != nil {
+			t.Fatal(err)
+		}
+		return &cm
+	}
+	if err := s.syncImport(ctx, "frontend", "bundle"); err != nil {
+		t.Fatal(err)
+	}
+	first := getConfigMap()
+	if first.Data[defaultBundleKey] != string(ca) {
+		t.Fatalf("got bundle %q, want the CA of the export", first.Data[defaultBundleKey])
+	}
+	if err := s.syncImport(ctx, "frontend", "bundle"); err != nil {
+		t.Fatal(err)
+	}
+	if second := getConfigMap(); second.ResourceVersion != first.ResourceVersion {
+		t.Errorf("unchanged sources updated the bundle: resourceVersion %s -> %s", first.ResourceVersion, second.ResourceVersion)
+	}
+}
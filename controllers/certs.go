@@ -0,0 +1,222 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// normalizePEM re-encodes PEM data with LF line endings, standard 64-column
+// base64 lines and exactly one trailing newline, dropping whitespace around
+// and between blocks. Data that is not entirely made of PEM blocks, such as
+// a keystore or a password, is returned unchanged.
+func normalizePEM(data []byte) []byte {
+	rest := bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+	var out bytes.Buffer
+	for len(bytes.TrimSpace(rest)) > 0 {
+		// pem.Decode skips text before a block, which must be kept
+		if !bytes.HasPrefix(bytes.TrimSpace(rest), []byte("-----BEGIN ")) {
+			return data
+		}
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return data
+		}
+		if err := pem.Encode(&out, block); err != nil {
+			return data
+		}
+	}
+	if out.Len() == 0 {
+		return data
+	}
+	return out.Bytes()
+}
+
+// normalizePEMData applies normalizePEM to every value of data. The values
+// of data are not modified, so it may hold slices shared with the cache.
+func normalizePEMData(data map[string][]byte) map[string][]byte {
+	out := make(map[string][]byte, len(data))
+	for k, v := range data {
+		out[k] = normalizePEM(v)
+	}
+	return out
+}
+
+// leafFingerprint returns the lowercase hex SHA-256 of the DER of the leaf
+// certificate in pemData.
+func leafFingerprint(pemData []byte) (string, error) {
+	leaf, err := leafCertificate(pemData)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(leaf.Raw)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// normalizeFingerprint strips colons and spaces from a hex fingerprint and
+// lowercases it, so 'AB:CD:...' as printed by openssl matches.
+func normalizeFingerprint(fp string) string {
+	return strings.ToLower(strings.NewReplacer(":", "", " ", "").Replace(fp))
+}
+
+// leafCertificate parses the first CERTIFICATE block of pemData, which by
+// convention is the leaf when tls.crt holds a chain.
+func leafCertificate(pemData []byte) (*x509.Certificate, error) {
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return nil, fmt.Errorf("no certificate found in PEM data")
+		}
+		if block.Type == "CERTIFICATE" {
+			return x509.ParseCertificate(block.Bytes)
+		}
+	}
+}
+
+// parseCertificates parses every CERTIFICATE block of pemData, in order.
+func parseCertificates(pemData []byte) ([]*x509.Certificate, error) {
+	var certs []*x509.Certificate
+	rest := pemData
+	for {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			return certs, nil
+		}
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		crt, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, err
+		}
+		certs = append(certs, crt)
+	}
+}
+
+// checkSourceData returns why a source secret of secretType has no data to
+// mirror yet, e.g. a placeholder created before the SealedSecret or
+// ExternalSecret behind it is filled in: a kubernetes.io/tls source with an
+// empty tls.crt or tls.key, a kubernetes.io/dockerconfigjson source with an
+// empty .dockerconfigjson, or any other source without a non-empty value.
+func checkSourceData(secretType corev1.SecretType, data map[string][]byte) error {
+	empty := func(key string) bool { return len(bytes.TrimSpace(data[key])) == 0 }
+	switch secretType {
+	case corev1.SecretTypeTLS:
+		for _, key := range []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey} {
+			if empty(key) {
+				return fmt.Errorf("%w: %s is missing or empty", ErrSourceEmpty, key)
+			}
+		}
+	case corev1.SecretTypeDockerConfigJson:
+		if empty(corev1.DockerConfigJsonKey) {
+			return fmt.Errorf("%w: %s is missing or empty", ErrSourceEmpty, corev1.DockerConfigJsonKey)
+		}
+	default:
+		for key := range data {
+			if !empty(key) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%w: no key has a value", ErrSourceEmpty)
+	}
+	return nil
+}
+
+// checkSourceReady returns why the certificate in source data is not fit to
+// be mirrored yet, e.g. while cert-manager is mid-renewal: tls.crt is empty
+// or does not parse, the leaf is outside its validity period, or a
+// kubernetes.io/tls source has an empty tls.key.
+func checkSourceReady(secretType corev1.SecretType, data map[string][]byte, now time.Time) error {
+	crt := data[corev1.TLSCertKey]
+	if len(bytes.TrimSpace(crt)) == 0 {
+		return fmt.Errorf("%w: tls.crt is missing or empty", ErrSourceNotReady)
+	}
+	if secretType == corev1.SecretTypeTLS && len(bytes.TrimSpace(data[corev1.TLSPrivateKeyKey])) == 0 {
+		return fmt.Errorf("%w: tls.key is empty", ErrSourceNotReady)
+	}
+	leaf, err := leafCertificate(crt)
+	if err != nil {
+		return fmt.Errorf("%w: tls.crt does not parse: %v", ErrSourceNotReady, err)
+	}
+	if now.Before(leaf.NotBefore) {
+		return fmt.Errorf("%w: certificate is not valid before %s", ErrSourceNotReady, leaf.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if now.After(leaf.NotAfter) {
+		return fmt.Errorf("%w: certificate expired at %s", ErrSourceNotReady, leaf.NotAfter.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// setCertificateStatus records status.notBefore and status.notAfter of the
+// leaf certificate in data's tls.crt. The fields are cleared when there is no
+// parseable tls.crt, e.g. for CA-only data.
+func setCertificateStatus(obj *unstructured.Unstructured, data map[string][]byte) {
+	crt, ok := data[corev1.TLSCertKey]
+	if ok {
+		if leaf, err := leafCertificate(crt); err == nil {
+			setString(obj.Object, "status.notBefore", leaf.NotBefore.UTC().Format(time.RFC3339))
+			setString(obj.Object, "status.notAfter", leaf.NotAfter.UTC().Format(time.RFC3339))
+			return
+		}
+	}
+	unstructured.RemoveNestedField(obj.Object, "status", "notBefore")
+	unstructured.RemoveNestedField(obj.Object, "status", "notAfter")
+}
+
+// checkExpiry exports the leaf certificate expiry of an import as a metric
+// and flags it ExpiringSoon, with a Warning event, once notAfter is within
+// the configured threshold (inclusive).
+func (s *SyncController) checkExpiry(imp *unstructured.Unstructured, data map[string][]byte) {
+	leaf, err := leafCertificate(data[corev1.TLSCertKey])
+	if err != nil {
+		certExpiry.DeleteLabelValues(imp.GetNamespace(), imp.GetName())
+		removeCondition(imp, conditionExpiringSoon)
+		return
+	}
+	certExpiry.WithLabelValues(imp.GetNamespace(), imp.GetName()).Set(float64(leaf.NotAfter.Unix()))
+	if !expiringSoon(leaf.NotAfter, time.Now(), s.opts.ExpiryWarningThreshold) {
+		removeCondition(imp, conditionExpiringSoon)
+		return
+	}
+	msg := fmt.Sprintf("certificate %q expires at %s", leaf.Subject.CommonName, leaf.NotAfter.UTC().Format(time.RFC3339))
+	setCondition(imp, conditionExpiringSoon, metav1.ConditionTrue, reasonCertificateExpiring, msg)
+	if s.recorder != nil {
+		s.recorder.Event(imp, corev1.EventTypeWarning, conditionExpiringSoon, msg)
+	}
+}
+
+// expiringSoon reports whether notAfter is within threshold of now. A
+// certificate expiring exactly at now+threshold counts as expiring.
+func expiringSoon(notAfter, now time.Time, threshold time.Duration) bool {
+	if threshold <= 0 {
+		return false
+	}
+	return !notAfter.After(now.Add(threshold))
+}
@@ -0,0 +1,117 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestExpiringSoon(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	threshold := 14 * 24 * time.Hour
+	tests := []struct {
+		name      string
+		notAfter  time.Time
+		threshold time.Duration
+		want      bool
+	}{
+		{name: "exactly at the threshold", notAfter: now.Add(threshold), threshold: threshold, want: true},
+		{name: "just inside the threshold", notAfter: now.Add(threshold - time.Second), threshold: threshold, want: true},
+		{name: "just outside the threshold", notAfter: now.Add(threshold + time.Second), threshold: threshold},
+		{name: "already expired", notAfter: now.Add(-time.Hour), threshold: threshold, want: true},
+		{name: "disabled", notAfter: now.Add(time.Second), threshold: 0},
+		{name: "disabled for an expired certificate", notAfter: now.Add(-time.Hour), threshold: 0},
+	}
+	for _, tt := range tests {
+		if got := expiringSoon(tt.notAfter, now, tt.threshold); got != tt.want {
+			t.Errorf("%s: got %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSyncImportExpiringSoon(t *testing.T) {
+	// newKeyPair certificates expire in 24h
+	tests := []struct {
+		name      string
+		threshold time.Duration
+		want      bool
+	}{
+		{name: "within the threshold", threshold: 48 * time.Hour, want: true},
+		{name: "outside the threshold", threshold: time.Hour},
+		{name: "disabled", threshold: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crt, key := newKeyPair(t, "app")
+			s, c := newTestController(t, Options{ExpiryWarningThreshold: tt.threshold},
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+			)
+			recorder := record.NewFakeRecorder(10)
+			s.recorder = recorder
+			if err := s.syncImport(context.Background(), "frontend", "app"); err != nil {
+				t.Fatal(err)
+			}
+			cond := getImportCondition(t, c, "frontend", "app", conditionExpiringSoon)
+			if got := cond != nil && cond.Status == metav1.ConditionTrue; got != tt.want {
+				t.Errorf("got ExpiringSoon %v, want %t", cond, tt.want)
+			}
+			var warned bool
+			for len(recorder.Events) > 0 {
+				if strings.HasPrefix(<-recorder.Events, corev1.EventTypeWarning+" "+conditionExpiringSoon) {
+					warned = true
+				}
+			}
+			if warned != tt.want {
+				t.Errorf("got warning event %t, want %t", warned, tt.want)
+			}
+		})
+	}
+}
+
+func TestCheckSourceData(t *testing.T) {
+	tests := []struct {
+		name       string
+		secretType corev1.SecretType
+		data       map[string][]byte
+		wantEmpty  bool
+	}{
+		{name: "tls pair", secretType: corev1.SecretTypeTLS, data: map[string][]byte{"tls.crt": []byte("crt"), "tls.key": []byte("key")}},
+		{name: "tls without key", secretType: corev1.SecretTypeTLS, data: map[string][]byte{"tls.crt": []byte("crt")}, wantEmpty: true},
+		{name: "tls with blank cert", secretType: corev1.SecretTypeTLS, data: map[string][]byte{"tls.crt": []byte(" \n"), "tls.key": []byte("key")}, wantEmpty: true},
+		{name: "pull secret", secretType: corev1.SecretTypeDockerConfigJson, data: map[string][]byte{".dockerconfigjson": []byte("{}")}},
+		{name: "empty pull secret", secretType: corev1.SecretTypeDockerConfigJson, data: map[string][]byte{".dockerconfigjson": nil}, wantEmpty: true},
+		{name: "opaque with a value", secretType: corev1.SecretTypeOpaque, data: map[string][]byte{"a": nil, "ca.crt": []byte("ca")}},
+		{name: "opaque without values", secretType: corev1.SecretTypeOpaque, data: map[string][]byte{"a": nil, "b": []byte("  ")}, wantEmpty: true},
+		{name: "opaque without keys", secretType: corev1.SecretTypeOpaque, wantEmpty: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkSourceData(tt.secretType, tt.data)
+			if tt.wantEmpty != errors.Is(err, ErrSourceEmpty) || !tt.wantEmpty && err != nil {
+				t.Errorf("got %v, want empty %v", err, tt.wantEmpty)
+			}
+		})
+	}
+}
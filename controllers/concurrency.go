@@ -0,0 +1,111 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+// errShuttingDown is returned for syncs started after shutdown began.
+var errShuttingDown = errors.New("controller is shutting down")
+
+// acquireSync waits for a free sync slot when Options.MaxConcurrentSyncs is
+// set and counts the sync of key, a scheduleKey, as in flight. The returned
+// func releases the slot and must be called exactly once, typically
+// deferred. No new syncs start once shutdown began.
+func (s *SyncController) acquireSync(ctx context.Context, key string) (func(), error) {
+	if s.syncSlots != nil {
+		pendingSyncs.Inc()
+		var err error
+		select {
+		case s.syncSlots <- struct{}{}:
+		case <-ctx.Done():
+			err = ctx.Err()
+		case <-s.stopping:
+			err = errShuttingDown
+		}
+		pendingSyncs.Dec()
+		if err != nil {
+			return nil, err
+		}
+	}
+	s.runningMu.Lock()
+	select {
+	case <-s.stopping:
+		s.runningMu.Unlock()
+		if s.syncSlots != nil {
+			<-s.syncSlots
+		}
+		return nil, errShuttingDown
+	default:
+	}
+	s.running[key]++
+	s.runningMu.Unlock()
+	syncsInFlight.Inc()
+	return func() {
+		syncsInFlight.Dec()
+		s.runningMu.Lock()
+		if s.running[key]--; s.running[key] == 0 {
+			delete(s.running, key)
+		}
+		s.runningMu.Unlock()
+		if s.syncSlots != nil {
+			<-s.syncSlots
+		}
+	}, nil
+}
+
+// runningSyncs returns the keys of the syncs in flight, sorted.
+func (s *SyncController) runningSyncs() []string {
+	s.runningMu.Lock()
+	defer s.runningMu.Unlock()
+	keys := make([]string, 0, len(s.running))
+	for key := range s.running {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// drain waits for the cron jobs, which stopped once cronDone is closed, and
+// every sync in flight to finish, so no target is left half-written. It gives
+// up after Options.ShutdownTimeout and logs the syncs it abandons.
+func (s *SyncController) drain(logger logr.Logger, cronDone <-chan struct{}) {
+	timeout := time.NewTimer(s.opts.ShutdownTimeout)
+	defer timeout.Stop()
+	poll := time.NewTicker(100 * time.Millisecond)
+	defer poll.Stop()
+	for {
+		select {
+		case <-cronDone:
+			if len(s.runningSyncs()) == 0 {
+				logger.Info("all syncs finished")
+				return
+			}
+		default:
+		}
+		select {
+		case <-timeout.C:
+			logger.Info("shutdown timeout reached, abandoning running syncs", "timeout", s.opts.ShutdownTimeout, "syncs", s.runningSyncs())
+			return
+		case <-poll.C:
+		}
+	}
+}
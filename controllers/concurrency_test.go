@@ -0,0 +1,153 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestSyncImportConcurrencyLimit(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	objs := []client.Object{
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+	}
+	const imports = 8
+	for i := 0; i < imports; i++ {
+		objs = append(objs, newImport("frontend", fmt.Sprintf("app-%d", i), map[string]interface{}{"fromExport": "backend/app", "targetSecret": fmt.Sprintf("app-%d-tls", i)}))
+	}
+
+	// every sync starts by reading its import; record how many run then
+	var s *SyncController
+	var mu sync.Mutex
+	var maxInFlight int
+	s, _ = newInterceptedTestController(t, Options{MaxConcurrentSyncs: 2}, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if key.Namespace == "frontend" {
+				mu.Lock()
+				maxInFlight = max(maxInFlight, len(s.runningSyncs()))
+				mu.Unlock()
+				time.Sleep(10 * time.Millisecond)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}, objs...)
+
+	var wg sync.WaitGroup
+	// the extra import does not exist; its failing sync must release its slot
+	for i := 0; i <= imports; i++ {
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			_ = s.syncImport(context.Background(), "frontend", name)
+		}(fmt.Sprintf("app-%d", i))
+	}
+	wg.Wait()
+	if maxInFlight == 0 || maxInFlight > 2 {
+		t.Errorf("got at most %d syncs in flight, want 1 or 2", maxInFlight)
+	}
+	if running := s.runningSyncs(); len(running) != 0 {
+		t.Errorf("got syncs %v still counted as running", running)
+	}
+	if n := len(s.syncSlots); n != 0 {
+		t.Errorf("got %d sync slots still taken", n)
+	}
+}
+
+// blockingImport returns interceptor funcs holding every read of an import
+// until release is closed. started is signalled on the first read.
+func blockingImport(started chan<- struct{}, release <-chan struct{}) interceptor.Funcs {
+	return interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if u, ok := obj.(*unstructured.Unstructured); ok && u.GetKind() == "CertificateImport" {
+				select {
+				case started <- struct{}{}:
+				default:
+				}
+				<-release
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	}
+}
+
+func TestStartWaitsForRunningSyncs(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	s, c := newInterceptedTestController(t, Options{ShutdownTimeout: 10 * time.Second}, blockingImport(started, release),
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- s.Start(ctx) }()
+
+	synced := make(chan error, 1)
+	go func() { synced <- s.syncImport(context.Background(), "frontend", "app") }()
+	<-started
+	cancel()
+	select {
+	case <-stopped:
+		t.Fatal("Start returned while a sync was running")
+	case <-time.After(200 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return once the sync finished")
+	}
+	if err := <-synced; err != nil {
+		t.Fatalf("the running sync failed: %v", err)
+	}
+	if getSecret(t, c, "frontend", "app-tls") == nil {
+		t.Error("the running sync did not write its target")
+	}
+	if err := s.syncImport(context.Background(), "frontend", "app"); err != errShuttingDown {
+		t.Errorf("got error %v for a sync after shutdown, want %v", err, errShuttingDown)
+	}
+}
+
+func TestStartAbandonsSyncsAfterTimeout(t *testing.T) {
+	started, release := make(chan struct{}, 1), make(chan struct{})
+	defer close(release)
+	s, _ := newInterceptedTestController(t, Options{ShutdownTimeout: 200 * time.Millisecond}, blockingImport(started, release),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	stopped := make(chan error)
+	go func() { stopped <- s.Start(ctx) }()
+	go func() { _ = s.syncImport(context.Background(), "frontend", "app") }()
+	<-started
+	cancel()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("Start did not return after the shutdown timeout")
+	}
+	if running := s.runningSyncs(); len(running) != 1 {
+		t.Errorf("got running syncs %v, want the abandoned one", running)
+	}
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Condition types reported in status.conditions.
+const (
+	// conditionConflict is set when the target secret is owned by someone else.
+	conditionConflict = "Conflict"
+	// conditionInvalidCertificate is set when the source key pair is invalid.
+	conditionInvalidCertificate = "InvalidCertificate"
+	// conditionExpiringSoon is set when the leaf certificate nears expiry.
+	conditionExpiringSoon = "ExpiringSoon"
+	// conditionSuspended is set while spec.suspend pauses syncing.
+	conditionSuspended = "Suspended"
+	// conditionRemoteUnreachable is set while a remote export's cluster
+	// can't be reached.
+	conditionRemoteUnreachable = "RemoteUnreachable"
+	// conditionNotAuthorized is set when the export does not allow the
+	// import's namespace.
+	conditionNotAuthorized = "NotAuthorized"
+	// conditionCyclicReference is set when the target secret feeds back into
+	// the import's own source.
+	conditionCyclicReference = "CyclicReference"
+	// conditionInvalidReference is set when spec.fromExport or
+	// spec.fromExports holds a malformed reference.
+	conditionInvalidReference = "InvalidReference"
+	// conditionInvalidTargetName is set when spec.targetSecret or
+	// spec.targetConfigMap is not a valid object name.
+	conditionInvalidTargetName = "InvalidTargetName"
+	// conditionSourceEmpty is set while spec.waitForSourceData holds back a
+	// source secret whose data is not filled in yet.
+	conditionSourceEmpty = "SourceEmpty"
+	// conditionSourceNotReady is set while spec.waitForValidSource holds
+	// back an invalid or expired source certificate.
+	conditionSourceNotReady = "SourceNotReady"
+	// conditionFingerprintMismatch is set when the source leaf certificate
+	// does not match spec.expectedFingerprint.
+	conditionFingerprintMismatch = "FingerprintMismatch"
+	// conditionSecretTooLarge is set when the target secret data exceeds
+	// --max-secret-size and is not written.
+	conditionSecretTooLarge = "SecretTooLarge"
+	// conditionSourceValid reports whether an export's source secret exists
+	// and has an accepted type. Unlike the others it is always present once
+	// the export was validated.
+	conditionSourceValid = "SourceValid"
+)
+
+// Condition reasons.
+const (
+	reasonTargetNotManaged        = "TargetNotManaged"
+	reasonDuplicateTarget         = "DuplicateTarget"
+	reasonInvalidKeyPair          = "InvalidKeyPair"
+	reasonCertificateExpiring     = "CertificateExpiring"
+	reasonSuspended               = "SuspendedBySpec"
+	reasonPaused                  = "PausedByAnnotation"
+	reasonConnectionFailed        = "ConnectionFailed"
+	reasonNamespaceNotAllowed     = "NamespaceNotAllowed"
+	reasonSelfReference           = "SelfReference"
+	reasonImportCycle             = "ImportCycle"
+	reasonMalformedReference      = "MalformedReference"
+	reasonCrossNamespaceReference = "CrossNamespaceReference"
+	reasonInvalidName             = "InvalidName"
+	reasonInvalidSource           = "InvalidSource"
+	reasonNotPopulated            = "NotPopulated"
+	reasonSourceFound             = "SourceFound"
+	reasonSizeLimitExceeded       = "SizeLimitExceeded"
+	reasonUnexpectedCertificate   = "UnexpectedCertificate"
+)
+
+// getConditions decodes status.conditions of obj. Malformed entries are dropped.
+func getConditions(obj *unstructured.Unstructured) []metav1.Condition {
+	raw, _, _ := unstructured.NestedSlice(obj.Object, "status", "conditions")
+	conds := make([]metav1.Condition, 0, len(raw))
+	for _, r := range raw {
+		m, ok := r.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		var c metav1.Condition
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(m, &c); err != nil {
+			continue
+		}
+		conds = append(conds, c)
+	}
+	return conds
+}
+
+// putConditions encodes conds into status.conditions of obj.
+func putConditions(obj *unstructured.Unstructured, conds []metav1.Condition) {
+	raw := make([]interface{}, 0, len(conds))
+	for i := range conds {
+		m, err := runtime.DefaultUnstructuredConverter.ToUnstructured(&conds[i])
+		if err != nil {
+			continue
+		}
+		raw = append(raw, m)
+	}
+	_ = unstructured.SetNestedSlice(obj.Object, raw, "status", "conditions")
+}
+
+// setCondition adds or updates a condition on obj's status and reports
+// whether anything changed. The transition time only changes when the
+// condition status changes.
+func setCondition(obj *unstructured.Unstructured, condType string, status metav1.ConditionStatus, reason, message string) bool {
+	conds := getConditions(obj)
+	changed := meta.SetStatusCondition(&conds, metav1.Condition{
+		Type:               condType,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: obj.GetGeneration(),
+	})
+	if changed {
+		putConditions(obj, conds)
+	}
+	return changed
+}
+
+// removeCondition drops a condition from obj's status if present and
+// reports whether it was removed.
+func removeCondition(obj *unstructured.Unstructured, condType string) bool {
+	conds := getConditions(obj)
+	if !meta.RemoveStatusCondition(&conds, condType) {
+		return false
+	}
+	putConditions(obj, conds)
+	return true
+}
+
+// removeConditionWithReason drops condType only when it was set for reason,
+// leaving conditions raised by other checks in place.
+func removeConditionWithReason(obj *unstructured.Unstructured, condType, reason string) bool {
+	c := meta.FindStatusCondition(getConditions(obj), condType)
+	if c == nil || c.Reason != reason {
+		return false
+	}
+	return removeCondition(obj, condType)
+}
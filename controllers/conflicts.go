@@ -0,0 +1,114 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// resolveTargetConflicts finds imports in the same namespace that share a
+// targetSecret. All of them get a Conflict condition, and only the winner
+// (oldest import, then lowest name) is kept in the returned slice so it alone
+// writes the secret. Imports without a conflict have a stale DuplicateTarget
+// condition cleared.
+func (s *SyncController) resolveTargetConflicts(ctx context.Context, items []unstructured.Unstructured) []unstructured.Unstructured {
+	groups := map[string][]*unstructured.Unstructured{}
+	var order []string
+	for i := range items {
+		item := &items[i]
+		key := importTarget(item)
+		if _, ok := groups[key]; !ok {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], item)
+	}
+
+	out := make([]unstructured.Unstructured, 0, len(items))
+	for _, key := range order {
+		group := groups[key]
+		if len(group) == 1 {
+			_ = s.updateStatus(ctx, group[0], func(imp *unstructured.Unstructured) bool {
+				return removeConditionWithReason(imp, conditionConflict, reasonDuplicateTarget)
+			})
+			out = append(out, *group[0])
+			continue
+		}
+
+		sort.Slice(group, func(i, j int) bool {
+			ti, tj := group[i].GetCreationTimestamp(), group[j].GetCreationTimestamp()
+			if !ti.Equal(&tj) {
+				return ti.Before(&tj)
+			}
+			return group[i].GetName() < group[j].GetName()
+		})
+		names := make([]string, 0, len(group))
+		for _, item := range group {
+			names = append(names, item.GetName())
+		}
+		winner := group[0]
+		log.FromContext(ctx).Info("conflicting imports target the same secret",
+			"target", key, "imports", strings.Join(names, ","), "winner", winner.GetName())
+
+		for i, item := range group {
+			msg := fmt.Sprintf("target %s is also targeted by imports %s; only %s writes it", key, strings.Join(names, ", "), winner.GetName())
+			_ = s.updateStatus(ctx, item, func(imp *unstructured.Unstructured) bool {
+				return setCondition(imp, conditionConflict, metav1.ConditionTrue, reasonDuplicateTarget, msg)
+			})
+			if i == 0 {
+				out = append(out, *item)
+			}
+		}
+	}
+	return out
+}
+
+// importTarget identifies the object an import writes, e.g.
+// "secret ns/name" or "configmap ns/name".
+func importTarget(imp *unstructured.Unstructured) string {
+	if cm := getString(imp.Object, "spec.targetConfigMap"); cm != "" {
+		return fmt.Sprintf("configmap %s/%s", imp.GetNamespace(), cm)
+	}
+	return fmt.Sprintf("secret %s/%s", imp.GetNamespace(), getString(imp.Object, "spec.targetSecret"))
+}
+
+// checkTargetName validates the name of the secret or configmap imp writes
+// against the Kubernetes naming rules and reflects the outcome in its
+// InvalidTargetName condition, so an invalid name fails the sync with a
+// clear error instead of a rejected write on every run.
+func (s *SyncController) checkTargetName(ctx context.Context, imp *unstructured.Unstructured) error {
+	field, value := "spec.targetSecret", getString(imp.Object, "spec.targetSecret")
+	if cm := getString(imp.Object, "spec.targetConfigMap"); cm != "" {
+		field, value = "spec.targetConfigMap", cm
+	}
+	var nameErr error
+	if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+		nameErr = fmt.Errorf("%w: import %s/%s: %s %q is not a valid name: %s", ErrInvalidTargetName, imp.GetNamespace(), imp.GetName(), field, value, strings.Join(errs, ", "))
+	}
+	_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		if nameErr == nil {
+			return removeCondition(imp, conditionInvalidTargetName)
+		}
+		return setCondition(imp, conditionInvalidTargetName, metav1.ConditionTrue, reasonInvalidName, nameErr.Error())
+	})
+	return nameErr
+}
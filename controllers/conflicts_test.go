@@ -0,0 +1,104 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestResolveTargetConflicts(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	created := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	// sharing returns an import of frontend/app-tls created age after created
+	sharing := func(name string, age time.Duration) *unstructured.Unstructured {
+		imp := newImport("frontend", name, map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"})
+		imp.SetCreationTimestamp(metav1.NewTime(created.Add(age)))
+		return imp
+	}
+
+	tests := []struct {
+		name       string
+		imports    []*unstructured.Unstructured
+		wantWinner string
+	}{
+		{
+			name:       "oldest import wins",
+			imports:    []*unstructured.Unstructured{sharing("alpha", time.Hour), sharing("zeta", 0)},
+			wantWinner: "zeta",
+		},
+		{
+			name:       "lowest name wins a tie",
+			imports:    []*unstructured.Unstructured{sharing("zeta", 0), sharing("alpha", 0), sharing("mid", 0)},
+			wantWinner: "alpha",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, reversed := range []bool{false, true} {
+				objs := []client.Object{
+					newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+					newExport("backend", "app", "app-tls"),
+				}
+				items := make([]unstructured.Unstructured, 0, len(tt.imports))
+				for _, imp := range tt.imports {
+					objs = append(objs, imp.DeepCopy())
+					items = append(items, *imp.DeepCopy())
+				}
+				s, c := newTestController(t, Options{}, objs...)
+				if reversed {
+					for i, j := 0, len(items)-1; i < j; i, j = i+1, j-1 {
+						items[i], items[j] = items[j], items[i]
+					}
+				}
+
+				ctx := context.Background()
+				winners := s.resolveTargetConflicts(ctx, items)
+				if len(winners) != 1 || winners[0].GetName() != tt.wantWinner {
+					t.Fatalf("reversed=%v: got winners %v, want only %s", reversed, importNames(winners), tt.wantWinner)
+				}
+				for _, imp := range tt.imports {
+					cond := getImportCondition(t, c, "frontend", imp.GetName(), conditionConflict)
+					if cond == nil || cond.Reason != reasonDuplicateTarget {
+						t.Errorf("reversed=%v: import %s has Conflict condition %+v, want %s", reversed, imp.GetName(), cond, reasonDuplicateTarget)
+					}
+				}
+
+				// only the winner is scheduled, so only it writes the target
+				if err := s.syncImport(ctx, "frontend", winners[0].GetName()); err != nil {
+					t.Fatal(err)
+				}
+				if got := getSecret(t, c, "frontend", "app-tls").Annotations[managedByAnnotation]; got != "frontend/"+tt.wantWinner {
+					t.Errorf("reversed=%v: target managed by %q, want frontend/%s", reversed, got, tt.wantWinner)
+				}
+			}
+		})
+	}
+}
+
+// importNames returns the names of items, for messages.
+func importNames(items []unstructured.Unstructured) []string {
+	names := make([]string, 0, len(items))
+	for _, item := range items {
+		names = append(names, item.GetName())
+	}
+	return names
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// resolveCycles drops imports whose target secret feeds back into their own
+// source, either directly (the target is the source secret) or through the
+// exports and imports of other namespaces (A -> B -> A). Those imports get a
+// CyclicReference condition; the condition is cleared once the cycle is gone.
+func (s *SyncController) resolveCycles(ctx context.Context, imports, exports []unstructured.Unstructured) []unstructured.Unstructured {
+	sources := map[string]types.NamespacedName{}
+	for i := range exports {
+		sources[exports[i].GetKind()+" "+exports[i].GetNamespace()+"/"+exports[i].GetName()] = exportSource(&exports[i])
+	}
+
+	// Every secret-target import is an edge from its source secret to its target
+	type edge struct{ from, to types.NamespacedName }
+	edges := make([]*edge, len(imports))
+	next := map[types.NamespacedName][]types.NamespacedName{}
+	for i := range imports {
+		imp := &imports[i]
+		if getString(imp.Object, "spec.targetConfigMap") != "" {
+			continue
+		}
+		kind, key, err := exportKind(imp.GetNamespace(), fromExportOf(imp))
+		if err != nil {
+			continue
+		}
+		src, ok := sources[kind+" "+key.Namespace+"/"+key.Name]
+		if !ok {
+			continue
+		}
+		e := &edge{from: src, to: types.NamespacedName{Namespace: imp.GetNamespace(), Name: getString(imp.Object, "spec.targetSecret")}}
+		edges[i] = e
+		next[e.from] = append(next[e.from], e.to)
+	}
+
+	out := make([]unstructured.Unstructured, 0, len(imports))
+	for i := range imports {
+		imp := &imports[i]
+		var reason, msg string
+		switch e := edges[i]; {
+		case e != nil && e.from == e.to:
+			reason = reasonSelfReference
+			msg = fmt.Sprintf("target secret %s is the source secret of export %s", e.to, fromExportOf(imp))
+		case e != nil && reaches(next, e.to, e.from):
+			reason = reasonImportCycle
+			msg = fmt.Sprintf("target secret %s is copied back into source secret %s by other imports", e.to, e.from)
+		default:
+			_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+				return removeCondition(imp, conditionCyclicReference)
+			})
+			out = append(out, *imp)
+			continue
+		}
+		log.FromContext(ctx).Info("import forms a cycle, not scheduling it", "import", fmt.Sprintf("%s/%s", imp.GetNamespace(), imp.GetName()))
+		_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+			return setCondition(imp, conditionCyclicReference, metav1.ConditionTrue, reason, msg)
+		})
+	}
+	return out
+}
+
+// reaches reports whether to is reachable from from along next.
+func reaches(next map[types.NamespacedName][]types.NamespacedName, from, to types.NamespacedName) bool {
+	seen := map[types.NamespacedName]bool{from: true}
+	queue := []types.NamespacedName{from}
+	for len(queue) > 0 {
+		cur := queue[0]
+		queue = queue[1:]
+		if cur == to {
+			return true
+		}
+		for _, n := range next[cur] {
+			if !seen[n] {
+				seen[n] = true
+				queue = append(queue, n)
+			}
+		}
+	}
+	return false
+}
+
+// inCycle reports whether the import is flagged with a CyclicReference
+// condition, so unscheduled syncs (retries, drift) skip it too.
+func inCycle(imp *unstructured.Unstructured) bool {
+	return meta.IsStatusConditionTrue(getConditions(imp), conditionCyclicReference)
+}
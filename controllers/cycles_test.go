@@ -0,0 +1,138 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestSyncImportSelfReference(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("backend", "app", map[string]interface{}{"fromExport": "app", "targetSecret": "app-tls"}),
+	)
+	err := s.syncImport(context.Background(), "backend", "app")
+	if !errors.Is(err, ErrCyclicReference) {
+		t.Fatalf("got error %v, want %v", err, ErrCyclicReference)
+	}
+	cond := getImportCondition(t, c, "backend", "app", conditionCyclicReference)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonSelfReference {
+		t.Errorf("got CyclicReference condition %+v, want True/%s", cond, reasonSelfReference)
+	}
+	if got := getSecret(t, c, "backend", "app-tls"); !bytes.Equal(got.Data[corev1.TLSCertKey], crt) || got.Annotations[managedByAnnotation] != "" {
+		t.Error("the source secret was overwritten")
+	}
+}
+
+func TestResolveCycles(t *testing.T) {
+	// frontend/app copies backend's source into frontend/app-tls, which
+	// frontend exports and backend/back copies over backend's source again
+	imports := []unstructured.Unstructured{
+		*newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		*newImport("backend", "back", map[string]interface{}{"fromExport": "frontend/app", "targetSecret": "app-tls"}),
+		*newImport("web", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		*newImport("backend", "self", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	}
+	exports := []unstructured.Unstructured{
+		*newExport("backend", "app", "app-tls"),
+		*newExport("frontend", "app", "app-tls"),
+	}
+	objs := []client.Object{}
+	for i := range imports {
+		objs = append(objs, &imports[i])
+	}
+	s, c := newTestController(t, Options{}, objs...)
+	ctx := context.Background()
+
+	got := s.resolveCycles(ctx, imports, exports)
+	if len(got) != 1 || got[0].GetNamespace() != "web" {
+		t.Errorf("got %d schedulable imports, want only web/app", len(got))
+	}
+	tests := []struct {
+		namespace, name string
+		wantReason      string
+	}{
+		{namespace: "frontend", name: "app", wantReason: reasonImportCycle},
+		{namespace: "backend", name: "back", wantReason: reasonImportCycle},
+		{namespace: "backend", name: "self", wantReason: reasonSelfReference},
+		{namespace: "web", name: "app"},
+	}
+	for _, tt := range tests {
+		cond := getImportCondition(t, c, tt.namespace, tt.name, conditionCyclicReference)
+		if tt.wantReason == "" {
+			if cond != nil {
+				t.Errorf("%s/%s: got CyclicReference condition %+v, want none", tt.namespace, tt.name, cond)
+			}
+			continue
+		}
+		if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != tt.wantReason {
+			t.Errorf("%s/%s: got CyclicReference condition %+v, want True/%s", tt.namespace, tt.name, cond, tt.wantReason)
+		}
+	}
+
+	// retargeting backend/back breaks the cycle and clears the condition
+	var back unstructured.Unstructured
+	back.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "backend", Name: "back"}, &back); err != nil {
+		t.Fatal(err)
+	}
+	setString(back.Object, "spec.targetSecret", "frontend-tls")
+	if err := c.Update(ctx, &back); err != nil {
+		t.Fatal(err)
+	}
+	var front unstructured.Unstructured
+	front.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := c.Get(ctx, client.ObjectKey{Namespace: "frontend", Name: "app"}, &front); err != nil {
+		t.Fatal(err)
+	}
+	got = s.resolveCycles(ctx, []unstructured.Unstructured{front, back}, exports)
+	if len(got) != 2 {
+		t.Errorf("got %d schedulable imports, want 2 once the cycle is gone", len(got))
+	}
+	for _, name := range []string{"frontend/app", "backend/back"} {
+		ns, n, _ := strings.Cut(name, "/")
+		if cond := getImportCondition(t, c, ns, n, conditionCyclicReference); cond != nil {
+			t.Errorf("%s: got CyclicReference condition %+v after the cycle was broken", name, cond)
+		}
+	}
+}
+
+func TestSyncImportSkipsFlaggedCycle(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	imp := newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"})
+	setCondition(imp, conditionCyclicReference, metav1.ConditionTrue, reasonImportCycle, "cycle")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		imp,
+	)
+	if err := s.syncImport(context.Background(), "frontend", "app"); !errors.Is(err, ErrCyclicReference) {
+		t.Fatalf("got error %v, want %v", err, ErrCyclicReference)
+	}
+	if getSecret(t, c, "frontend", "app-tls") != nil {
+		t.Error("an import in a cycle wrote its target")
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// importExplanation describes what a sync of an import would do. It never
+// holds secret values, only key names.
+type importExplanation struct {
+	Import       string            `json:"import"`
+	Suspended    bool              `json:"suspended"`
+	ExportKind   string            `json:"exportKind,omitempty"`
+	Export       string            `json:"export,omitempty"`
+	SourceSecret string            `json:"sourceSecret,omitempty"`
+	SourceFound  bool              `json:"sourceFound"`
+	Target       string            `json:"target"`
+	TargetExists bool              `json:"targetExists"`
+	TargetType   corev1.SecretType `json:"targetType,omitempty"`
+	DesiredKeys  []string          `json:"desiredKeys,omitempty"`
+	// NoOp is set when the target already holds the desired data and type,
+	// so a sync would not change its content
+	NoOp  bool   `json:"noOp"`
+	Error string `json:"error,omitempty"`
+}
+
+// explainImport plans a sync of the import namespace/name the way syncImport
+// does, without writing anything. Problems that would fail the sync are
+// reported in the explanation; the error is only set when the import can't
+// be read.
+func (s *SyncController) explainImport(ctx context.Context, namespace, name string) (*importExplanation, error) {
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, imp); err != nil {
+		return nil, err
+	}
+	e := &importExplanation{Import: namespace + "/" + name, Suspended: isSuspended(imp), Target: importTarget(imp)}
+	if err := s.planExplanation(ctx, imp, e); err != nil {
+		e.Error = err.Error()
+	}
+	return e, nil
+}
+
+func (s *SyncController) planExplanation(ctx context.Context, imp *unstructured.Unstructured, e *importExplanation) error {
+	namespace, name := imp.GetNamespace(), imp.GetName()
+	if err := s.checkNamespaceAllowed(namespace); err != nil {
+		return err
+	}
+	typed, err := toImport(imp)
+	if err != nil {
+		return err
+	}
+	spec := &typed.Spec
+	if spec.TargetConfigMap != "" {
+		return errors.New("only imports with spec.targetSecret can be explained")
+	}
+
+	fromExport := fromExportOf(imp)
+	expKind, expKey, err := exportKind(namespace, fromExport)
+	if err != nil {
+		return err
+	}
+	e.ExportKind, e.Export = expKind, strings.TrimPrefix(expKey.String(), "/")
+	exp, err := getExport(ctx, s, namespace, fromExport)
+	if err != nil {
+		return err
+	}
+	if ok, err := importAllowed(ctx, s, exp, namespace); err != nil {
+		return err
+	} else if !ok {
+		return ErrNotAuthorized
+	}
+	srcKey := exportSource(exp)
+	e.SourceSecret = srcKey.String()
+	var src corev1.Secret
+	if err := s.getSourceSecret(ctx, srcKey, &src); err != nil {
+		return err
+	}
+	e.SourceFound = true
+	if err := checkSourceType(exp, &src); err != nil {
+		return err
+	}
+
+	srcData, err := s.importSourceData(ctx, imp, spec, &src)
+	if err != nil {
+		return err
+	}
+	selected, data, secretType, err := planImport(&corev1.Secret{ObjectMeta: src.ObjectMeta, Type: src.Type, Data: srcData}, spec, namespace, name)
+	if err != nil {
+		return err
+	}
+	e.TargetType = secretType
+
+	var current *corev1.Secret
+	var tgt corev1.Secret
+	if err := s.liveReader().Get(ctx, types.NamespacedName{Namespace: namespace, Name: spec.TargetSecret}, &tgt); err == nil {
+		current = &tgt
+		e.TargetExists = true
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+	if _, err := s.addKeystores(ctx, imp, selected, data, current); err != nil {
+		return err
+	}
+	for k := range data {
+		e.DesiredKeys = append(e.DesiredKeys, k)
+	}
+	sort.Strings(e.DesiredKeys)
+	if current != nil {
+		e.NoOp = current.Type == secretType && dataChecksum(current.Data) == dataChecksum(nextTargetData(current, data))
+	}
+	return nil
+}
+
+// nextTargetData returns the data current holds after a sync writing data:
+// keys it manages but no longer desires are dropped, desired keys are set,
+// and keys the controller did not write are kept.
+func nextTargetData(current *corev1.Secret, data map[string][]byte) map[string][]byte {
+	next := make(map[string][]byte, len(current.Data)+len(data))
+	for k, v := range current.Data {
+		next[k] = v
+	}
+	if prev, ok := current.Annotations[managedKeysAnnotation]; ok {
+		for _, k := range strings.Split(prev, ",") {
+			if _, ok := data[k]; !ok {
+				delete(next, k)
+			}
+		}
+	}
+	for k, v := range data {
+		next[k] = v
+	}
+	return next
+}
+
+// handleExplainImport serves GET /debug/imports/{namespace}/{name} with the
+// explanation of the import as JSON.
+func (s *SyncController) handleExplainImport(w http.ResponseWriter, r *http.Request) {
+	e, err := s.explainImport(r.Context(), r.PathValue("namespace"), r.PathValue("name"))
+	if err != nil {
+		status := http.StatusInternalServerError
+		if apierrors.IsNotFound(err) {
+			status = http.StatusNotFound
+		}
+		http.Error(w, err.Error(), status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	_ = enc.Encode(e)
+}
+
+// debugServer serves the read-only debug endpoints on
+// Options.DebugBindAddress. It runs on every replica, not only the leader.
+type debugServer struct {
+	s    *SyncController
+	addr string
+}
+
+func (d *debugServer) NeedLeaderElection() bool { return false }
+
+// handler routes the debug endpoints.
+func (d *debugServer) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /debug/imports/{namespace}/{name}", d.s.handleExplainImport)
+	return mux
+}
+
+func (d *debugServer) Start(ctx context.Context) error {
+	srv := &http.Server{Addr: d.addr, Handler: d.handler(), ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		_ = srv.Shutdown(context.Background())
+	}()
+	log.FromContext(ctx).Info("serving debug endpoints", "address", d.addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// explain requests the explanation of the import namespace/name from the
+// debug handler of s.
+func explain(t *testing.T, s *SyncController, namespace, name string) (int, *importExplanation, string) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	(&debugServer{s: s}).handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/debug/imports/"+namespace+"/"+name, nil))
+	if rec.Code != http.StatusOK {
+		return rec.Code, nil, rec.Body.String()
+	}
+	var e importExplanation
+	if err := json.Unmarshal(rec.Body.Bytes(), &e); err != nil {
+		t.Fatalf("invalid explanation %q: %v", rec.Body.String(), err)
+	}
+	return rec.Code, &e, rec.Body.String()
+}
+
+func TestHandleExplainImport(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, _ := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("frontend", "orphan", map[string]interface{}{"fromExport": "backend/missing", "targetSecret": "orphan-tls"}),
+	)
+
+	code, e, body := explain(t, s, "frontend", "app")
+	if code != http.StatusOK {
+		t.Fatalf("got status %d: %s", code, body)
+	}
+	want := &importExplanation{
+		Import:       "frontend/app",
+		ExportKind:   "CertificateExport",
+		Export:       "backend/app",
+		SourceSecret: "backend/app-tls",
+		SourceFound:  true,
+		Target:       "secret frontend/app-tls",
+		TargetType:   corev1.SecretTypeTLS,
+		DesiredKeys:  e.DesiredKeys,
+	}
+	if !reflect.DeepEqual(e, want) {
+		t.Errorf("got explanation %+v, want %+v", e, want)
+	}
+	if len(e.DesiredKeys) == 0 {
+		t.Error("got no desired keys")
+	}
+	if strings.Contains(body, string(key)) || strings.Contains(body, "PRIVATE KEY") {
+		t.Error("the explanation contains secret values")
+	}
+
+	// once synced, another sync would change nothing
+	if err := s.syncImport(context.Background(), "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if _, e, _ := explain(t, s, "frontend", "app"); !e.TargetExists || !e.NoOp {
+		t.Errorf("got targetExists %t, noOp %t after a sync, want both", e.TargetExists, e.NoOp)
+	}
+
+	// the import reads, but its sync would fail
+	if _, e, _ := explain(t, s, "frontend", "orphan"); e == nil || !strings.Contains(e.Error, "not found") || e.SourceFound {
+		t.Errorf("got explanation %+v for an import without its export, want a not found error", e)
+	}
+
+	if code, _, _ := explain(t, s, "frontend", "missing"); code != http.StatusNotFound {
+		t.Errorf("got status %d for a missing import, want %d", code, http.StatusNotFound)
+	}
+}
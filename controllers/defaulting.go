@@ -0,0 +1,67 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"net/http"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// mutatePath is where the defaulting webhook for the CRDs is served.
+const mutatePath = "/mutate-cert-trust-flolive-io-v1"
+
+// admissionDefaulter fills in omitted fields of CertificateImports and
+// exports with the defaults the controller would apply, so the stored
+// object shows the effective values.
+type admissionDefaulter struct{}
+
+func (d *admissionDefaulter) Handle(_ context.Context, req admission.Request) admission.Response {
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	if !applyDefaults(obj) {
+		return admission.Allowed("")
+	}
+	mutated, err := obj.MarshalJSON()
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+	return admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+}
+
+// applyDefaults sets spec.schedule to the default schedule and, on imports
+// writing a configmap, spec.targetConfigMapKey to defaultBundleKey, when
+// unset. It reports whether obj changed.
+func applyDefaults(obj *unstructured.Unstructured) bool {
+	switch obj.GetKind() {
+	case "CertificateImport", "CertificateExport", "ClusterCertificateExport":
+	default:
+		return false
+	}
+	changed := false
+	if getString(obj.Object, "spec.schedule") == "" {
+		setString(obj.Object, "spec.schedule", defaultSchedule)
+		changed = true
+	}
+	if obj.GetKind() == "CertificateImport" && getString(obj.Object, "spec.targetConfigMap") != "" && getString(obj.Object, "spec.targetConfigMapKey") == "" {
+		setString(obj.Object, "spec.targetConfigMapKey", defaultBundleKey)
+		changed = true
+	}
+	return changed
+}
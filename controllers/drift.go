@@ -0,0 +1,119 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// checksumAnnotation holds the SHA-256 of the data the controller last wrote
+// to a target secret. A mismatch means the secret was modified externally.
+const checksumAnnotation = annotationPrefix + "checksum"
+
+// dataChecksum returns a SHA-256 over data. Keys are sorted and every key and
+// value is length-prefixed, so distinct maps never produce the same input.
+func dataChecksum(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	h := sha256.New()
+	var n [8]byte
+	for _, k := range keys {
+		binary.BigEndian.PutUint64(n[:], uint64(len(k)))
+		h.Write(n[:])
+		h.Write([]byte(k))
+		binary.BigEndian.PutUint64(n[:], uint64(len(data[k])))
+		h.Write(n[:])
+		h.Write(data[k])
+	}
+	return fmt.Sprintf("%x", h.Sum(nil))
+}
+
+// managedSecretImport maps a target secret to the import that manages it, or
+// returns nil for secrets without the managed-by annotation.
+func managedSecretImport(_ context.Context, obj client.Object) []reconcile.Request {
+	managedBy := obj.GetAnnotations()[managedByAnnotation]
+	if managedBy == "" {
+		return nil
+	}
+	key, err := parseNSName(obj.GetNamespace(), managedBy)
+	if err != nil {
+		return nil
+	}
+	return []reconcile.Request{{NamespacedName: key}}
+}
+
+// driftPredicate passes managed target secrets whose data no longer matches
+// the checksum the controller wrote, and managed secrets that were deleted.
+// The controller's own writes carry a matching checksum and are ignored.
+func driftPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return false },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			sec, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok || sec.Annotations[managedByAnnotation] == "" {
+				return false
+			}
+			return sec.Annotations[checksumAnnotation] != dataChecksum(sec.Data)
+		},
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return e.Object.GetAnnotations()[managedByAnnotation] != ""
+		},
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// driftReconciler re-syncs an import as soon as its target secret drifts
+// from the source instead of waiting for the next scheduled run.
+type driftReconciler struct {
+	s *SyncController
+}
+
+func (r *driftReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := r.s.Get(ctx, req.NamespacedName, imp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+	logger := log.FromContext(ctx).WithValues("import", req.String())
+	logger.Info("target secret drifted from source, re-syncing import")
+	// runImportSync schedules its own retries; requeueing here would double
+	// them. A sync already in progress may have read the drifted target, though.
+	if err := r.s.runImportSync(ctx, req.Namespace, req.Name); errors.Is(err, errSyncInProgress) {
+		return reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		logger.Error(err, "failed to sync import")
+	}
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestDataChecksum(t *testing.T) {
+	a := dataChecksum(map[string][]byte{"ab": []byte("c")})
+	if b := dataChecksum(map[string][]byte{"a": []byte("bc")}); a == b {
+		t.Error("moving bytes between key and value kept the checksum")
+	}
+	if b := dataChecksum(map[string][]byte{"ab": []byte("c")}); a != b {
+		t.Error("the checksum of equal data changed")
+	}
+}
+
+func TestDriftPredicate(t *testing.T) {
+	data := map[string][]byte{"ca.crt": []byte("ca")}
+	secret := func(annotations map[string]string, data map[string][]byte) *corev1.Secret {
+		sec := newSecret("frontend", "app-tls", corev1.SecretTypeOpaque, data)
+		sec.Annotations = annotations
+		return sec
+	}
+	managed := map[string]string{managedByAnnotation: "app", checksumAnnotation: dataChecksum(data)}
+	tests := []struct {
+		name string
+		sec  *corev1.Secret
+		want bool
+	}{
+		{name: "unchanged", sec: secret(managed, data)},
+		{name: "drifted", sec: secret(managed, map[string][]byte{"ca.crt": []byte("other")}), want: true},
+		{name: "unmanaged", sec: secret(map[string]string{checksumAnnotation: dataChecksum(data)}, map[string][]byte{"ca.crt": []byte("other")})},
+	}
+	p := driftPredicate()
+	for _, tt := range tests {
+		if got := p.Update(event.UpdateEvent{ObjectOld: secret(managed, data), ObjectNew: tt.sec}); got != tt.want {
+			t.Errorf("%s: got %t, want %t", tt.name, got, tt.want)
+		}
+	}
+	if !p.Delete(event.DeleteEvent{Object: secret(managed, data)}) {
+		t.Error("deleting a managed secret was ignored")
+	}
+	if p.Create(event.CreateEvent{Object: secret(managed, data)}) {
+		t.Error("creating a managed secret was passed")
+	}
+}
+
+func TestDriftReconcilerRestoresTarget(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx := context.Background()
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	want := getSecret(t, c, "frontend", "app-tls").Data
+
+	target := getSecret(t, c, "frontend", "app-tls")
+	target.Data[corev1.TLSCertKey] = []byte("tampered")
+	if err := c.Update(ctx, target); err != nil {
+		t.Fatal(err)
+	}
+	if reqs := managedSecretImport(ctx, target); len(reqs) != 1 || reqs[0].NamespacedName != (types.NamespacedName{Namespace: "frontend", Name: "app"}) {
+		t.Fatalf("got requests %v for the target, want frontend/app", reqs)
+	}
+
+	r := &driftReconciler{s: s}
+	if _, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "frontend", Name: "app"}}); err != nil {
+		t.Fatal(err)
+	}
+	got := getSecret(t, c, "frontend", "app-tls")
+	if !reflect.DeepEqual(got.Data, want) {
+		t.Error("the drifted target was not restored")
+	}
+	if got.Annotations[checksumAnnotation] != dataChecksum(got.Data) {
+		t.Error("the restored target's checksum does not match its data")
+	}
+}
@@ -0,0 +1,160 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// dryRunClient reads through to the wrapped client but only logs writes, so
+// the controller computes every change without applying any of them.
+type dryRunClient struct {
+	client.Client
+}
+
+func newDryRunClient(c client.Client) client.Client {
+	return &dryRunClient{Client: c}
+}
+
+func (c *dryRunClient) Create(ctx context.Context, obj client.Object, _ ...client.CreateOption) error {
+	c.logWrite(ctx, "create", obj)
+	return nil
+}
+
+func (c *dryRunClient) Update(ctx context.Context, obj client.Object, _ ...client.UpdateOption) error {
+	c.logWrite(ctx, "update", obj)
+	return nil
+}
+
+func (c *dryRunClient) Patch(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+	c.logWrite(ctx, "patch", obj)
+	return nil
+}
+
+func (c *dryRunClient) Delete(ctx context.Context, obj client.Object, _ ...client.DeleteOption) error {
+	c.logWrite(ctx, "delete", obj)
+	return nil
+}
+
+func (c *dryRunClient) DeleteAllOf(ctx context.Context, obj client.Object, _ ...client.DeleteAllOfOption) error {
+	c.logWrite(ctx, "delete all of", obj)
+	return nil
+}
+
+func (c *dryRunClient) Status() client.SubResourceWriter {
+	return &dryRunSubResourceClient{c: c, subResource: "status"}
+}
+
+func (c *dryRunClient) SubResource(subResource string) client.SubResourceClient {
+	return &dryRunSubResourceClient{c: c, subResource: subResource}
+}
+
+// logWrite logs the write that would have been made. For secrets and
+// configmaps it also lists the keys that would be added, changed or removed
+// compared to the current object.
+func (c *dryRunClient) logWrite(ctx context.Context, action string, obj client.Object) {
+	kind := obj.GetObjectKind().GroupVersionKind().Kind
+	if gvk, err := c.GroupVersionKindFor(obj); err == nil {
+		kind = gvk.Kind
+	}
+	values := []interface{}{"action", action, "kind", kind, "namespace", obj.GetNamespace(), "name", obj.GetName()}
+	if desired, ok := objectKeys(obj); ok && action != "delete" {
+		current := map[string][]byte{}
+		if action != "create" {
+			existing := emptyLike(obj)
+			if err := c.Get(ctx, client.ObjectKeyFromObject(obj), existing); err == nil {
+				current, _ = objectKeys(existing)
+			}
+		}
+		added, changed, removed := diffKeys(current, desired)
+		values = append(values, "added", added, "changed", changed, "removed", removed)
+	}
+	log.FromContext(ctx).Info("dry run: skipping write", values...)
+}
+
+// objectKeys returns the data of a secret or configmap.
+func objectKeys(obj runtime.Object) (map[string][]byte, bool) {
+	switch o := obj.(type) {
+	case *corev1.Secret:
+		return o.Data, true
+	case *corev1.ConfigMap:
+		data := make(map[string][]byte, len(o.Data))
+		for k, v := range o.Data {
+			data[k] = []byte(v)
+		}
+		return data, true
+	}
+	return nil, false
+}
+
+// emptyLike returns a new, empty secret or configmap matching obj's type.
+func emptyLike(obj client.Object) client.Object {
+	if _, ok := obj.(*corev1.ConfigMap); ok {
+		return &corev1.ConfigMap{}
+	}
+	return &corev1.Secret{}
+}
+
+func diffKeys(current, desired map[string][]byte) (added, changed, removed []string) {
+	for k, v := range desired {
+		cur, ok := current[k]
+		switch {
+		case !ok:
+			added = append(added, k)
+		case string(cur) != string(v):
+			changed = append(changed, k)
+		}
+	}
+	for k := range current {
+		if _, ok := desired[k]; !ok {
+			removed = append(removed, k)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(changed)
+	sort.Strings(removed)
+	return added, changed, removed
+}
+
+// dryRunSubResourceClient only logs subresource writes, e.g. status updates.
+type dryRunSubResourceClient struct {
+	c           *dryRunClient
+	subResource string
+}
+
+func (s *dryRunSubResourceClient) Get(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceGetOption) error {
+	return s.c.Client.SubResource(s.subResource).Get(ctx, obj, subResource, opts...)
+}
+
+func (s *dryRunSubResourceClient) Create(ctx context.Context, obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+	s.c.logWrite(ctx, "create "+s.subResource, obj)
+	return nil
+}
+
+func (s *dryRunSubResourceClient) Update(ctx context.Context, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+	s.c.logWrite(ctx, "update "+s.subResource, obj)
+	return nil
+}
+
+func (s *dryRunSubResourceClient) Patch(ctx context.Context, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+	s.c.logWrite(ctx, "patch "+s.subResource, obj)
+	return nil
+}
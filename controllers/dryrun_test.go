@@ -0,0 +1,96 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestDryRunWritesNothing(t *testing.T) {
+	errWrite := errors.New("write in dry run")
+	fail := func(verb string, obj client.Object) error {
+		t.Errorf("dry run %s of %T %s/%s reached the API server", verb, obj, obj.GetNamespace(), obj.GetName())
+		return errWrite
+	}
+	funcs := interceptor.Funcs{
+		Create: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.CreateOption) error {
+			return fail("create", obj)
+		},
+		Update: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.UpdateOption) error {
+			return fail("update", obj)
+		},
+		Patch: func(_ context.Context, _ client.WithWatch, obj client.Object, _ client.Patch, _ ...client.PatchOption) error {
+			return fail("patch", obj)
+		},
+		Delete: func(_ context.Context, _ client.WithWatch, obj client.Object, _ ...client.DeleteOption) error {
+			return fail("delete", obj)
+		},
+		SubResourceCreate: func(_ context.Context, _ client.Client, sub string, obj client.Object, _ client.Object, _ ...client.SubResourceCreateOption) error {
+			return fail("create "+sub, obj)
+		},
+		SubResourceUpdate: func(_ context.Context, _ client.Client, sub string, obj client.Object, _ ...client.SubResourceUpdateOption) error {
+			return fail("update "+sub, obj)
+		},
+		SubResourcePatch: func(_ context.Context, _ client.Client, sub string, obj client.Object, _ client.Patch, _ ...client.SubResourcePatchOption) error {
+			return fail("patch "+sub, obj)
+		},
+	}
+	crt, key := newKeyPair(t, "app")
+	stale := newSecret("frontend", "stale-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte("old")})
+	stale.Annotations = map[string]string{managedByAnnotation: "frontend/stale"}
+	s, c := newInterceptedTestController(t, Options{DryRun: true}, funcs,
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key, "ca.crt": crt}),
+		newExport("backend", "app", "app-tls"),
+		newPushExport("backend", "push", "app-tls", "app-tls", "api"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("frontend", "stale", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "stale-tls"}),
+		newImport("frontend", "bundle", map[string]interface{}{"fromExports": []interface{}{"backend/app"}, "targetConfigMap": "ca-bundle"}),
+		stale,
+	)
+	// as wired up by the manager for --dry-run
+	s.Client = newDryRunClient(c)
+	ctx := context.Background()
+
+	for _, name := range []string{"app", "stale", "bundle"} {
+		if err := s.syncImport(ctx, "frontend", name); err != nil {
+			t.Errorf("dry run sync of frontend/%s: %v", name, err)
+		}
+	}
+	if err := s.syncExportPush(ctx, "backend", "push"); err != nil {
+		t.Errorf("dry run push: %v", err)
+	}
+	if getSecret(t, c, "frontend", "app-tls") != nil || getSecret(t, c, "api", "app-tls") != nil {
+		t.Error("a dry run created a secret")
+	}
+	if got := getSecret(t, c, "frontend", "stale-tls"); string(got.Data[corev1.TLSCertKey]) != "old" {
+		t.Error("a dry run updated a secret")
+	}
+}
+
+func TestDiffKeys(t *testing.T) {
+	current := map[string][]byte{"ca.crt": []byte("ca"), "tls.crt": []byte("old"), "tls.key": []byte("key")}
+	desired := map[string][]byte{"ca.crt": []byte("ca"), "tls.crt": []byte("new"), "extra": []byte("x")}
+	added, changed, removed := diffKeys(current, desired)
+	if !reflect.DeepEqual(added, []string{"extra"}) || !reflect.DeepEqual(changed, []string{"tls.crt"}) || !reflect.DeepEqual(removed, []string{"tls.key"}) {
+		t.Errorf("got added %v, changed %v, removed %v", added, changed, removed)
+	}
+}
@@ -0,0 +1,80 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Errors returned by syncs, wrapped with the namespace/name of the objects
+// involved. Use errors.Is to tell them apart; errors from the API server stay
+// in the chain, so apierrors.IsNotFound and friends keep working too.
+var (
+	// ErrExportNotFound means the export referenced by an import does not exist.
+	ErrExportNotFound = errors.New("export not found")
+	// ErrSourceSecretMissing means the source secret of an export does not exist.
+	ErrSourceSecretMissing = errors.New("source secret missing")
+	// ErrWrongSecretType means the source secret is not of a type the export accepts.
+	ErrWrongSecretType = errors.New("wrong secret type")
+	// ErrInvalidCertificate means the source certificate and key do not form
+	// a valid pair.
+	ErrInvalidCertificate = errors.New("invalid certificate")
+	// ErrNotAuthorized means an export does not allow the namespace of an import.
+	ErrNotAuthorized = errors.New("not authorized")
+	// ErrTargetNotManaged means the target exists but is managed by someone else.
+	ErrTargetNotManaged = errors.New("target not managed")
+	// ErrCyclicReference means writing the target would feed back into its
+	// own source.
+	ErrCyclicReference = errors.New("cyclic reference")
+	// ErrSourceEmpty means the source secret has no data to mirror yet while
+	// spec.waitForSourceData is set.
+	ErrSourceEmpty = errors.New("source empty")
+	// ErrSourceNotReady means the source certificate is empty, unparseable
+	// or outside its validity period while spec.waitForValidSource is set.
+	ErrSourceNotReady = errors.New("source not ready")
+	// ErrFingerprintMismatch means the source leaf certificate does not
+	// match spec.expectedFingerprint.
+	ErrFingerprintMismatch = errors.New("fingerprint mismatch")
+	// ErrInvalidReference means a reference such as spec.fromExport is not
+	// of the form name, namespace/name or cluster/name.
+	ErrInvalidReference = errors.New("invalid reference")
+	// ErrInvalidTargetName means spec.targetSecret or spec.targetConfigMap
+	// is not a valid object name.
+	ErrInvalidTargetName = errors.New("invalid target name")
+	// ErrSecretTooLarge means the data of a target secret exceeds
+	// Options.MaxSecretSize.
+	ErrSecretTooLarge = errors.New("secret too large")
+	// ErrPrivateKeyInBundle means a ca.crt to be bundled into a configmap
+	// holds a private key, which must never be written to a configmap.
+	ErrPrivateKeyInBundle = errors.New("ca.crt contains a private key; refusing to write it to a configmap")
+)
+
+// getSourceSecret reads the source secret key into src. A missing secret
+// is reported as ErrSourceSecretMissing.
+func (s *SyncController) getSourceSecret(ctx context.Context, key types.NamespacedName, src *corev1.Secret) error {
+	if err := s.Get(ctx, key, src); err != nil {
+		if apierrors.IsNotFound(err) {
+			return fmt.Errorf("%w: %s: %w", ErrSourceSecretMissing, key, err)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"errors"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// Event reasons recorded on CertificateImports and CertificateExports.
+const (
+	eventReasonSynced              = "Synced"
+	eventReasonSyncFailed          = "SyncFailed"
+	eventReasonSourceSecretMissing = "SourceSecretMissing"
+	eventReasonExportNotFound      = "ExportNotFound"
+	eventReasonInvalidCertificate  = "InvalidCertificate"
+	eventReasonNotAuthorized       = "NotAuthorized"
+	eventReasonWrongSecretType     = "WrongSecretType"
+	eventReasonTargetNotManaged    = "TargetNotManaged"
+	eventReasonCyclicReference     = "CyclicReference"
+	eventReasonInvalidReference    = "InvalidReference"
+	eventReasonSourceEmpty         = "SourceEmpty"
+	eventReasonSourceNotReady      = "SourceNotReady"
+	eventReasonFingerprintMismatch = "FingerprintMismatch"
+	eventReasonInvalidTargetName   = "InvalidTargetName"
+	eventReasonSecretTooLarge      = "SecretTooLarge"
+	eventReasonRolloutRestarted    = "RolloutRestarted"
+	eventReasonRolloutFailed       = "RolloutFailed"
+)
+
+// recordSyncResult emits a Normal event with message on success and a
+// Warning event describing err on failure.
+func (s *SyncController) recordSyncResult(obj runtime.Object, err error, message string) {
+	if s.recorder == nil || obj == nil {
+		return
+	}
+	if err != nil {
+		s.recorder.Event(obj, corev1.EventTypeWarning, eventReasonFor(err), err.Error())
+		return
+	}
+	s.recorder.Event(obj, corev1.EventTypeNormal, eventReasonSynced, message)
+}
+
+// eventReasonFor maps a sync error to an event reason. Each sentinel error of
+// the package gets a dedicated reason, everything else is SyncFailed.
+func eventReasonFor(err error) string {
+	switch {
+	case errors.Is(err, ErrInvalidCertificate):
+		return eventReasonInvalidCertificate
+	case errors.Is(err, ErrNotAuthorized):
+		return eventReasonNotAuthorized
+	case errors.Is(err, ErrSourceSecretMissing):
+		return eventReasonSourceSecretMissing
+	case errors.Is(err, ErrExportNotFound):
+		return eventReasonExportNotFound
+	case errors.Is(err, ErrWrongSecretType):
+		return eventReasonWrongSecretType
+	case errors.Is(err, ErrTargetNotManaged):
+		return eventReasonTargetNotManaged
+	case errors.Is(err, ErrCyclicReference):
+		return eventReasonCyclicReference
+	case errors.Is(err, ErrSourceEmpty):
+		return eventReasonSourceEmpty
+	case errors.Is(err, ErrSourceNotReady):
+		return eventReasonSourceNotReady
+	case errors.Is(err, ErrFingerprintMismatch):
+		return eventReasonFingerprintMismatch
+	case errors.Is(err, ErrInvalidReference):
+		return eventReasonInvalidReference
+	case errors.Is(err, ErrInvalidTargetName):
+		return eventReasonInvalidTargetName
+	case errors.Is(err, ErrSecretTooLarge):
+		return eventReasonSecretTooLarge
+	}
+	return eventReasonSyncFailed
+}
@@ -0,0 +1,82 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+func TestSyncImportEvents(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, _ := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("frontend", "orphan", map[string]interface{}{"fromExport": "backend/missing", "targetSecret": "orphan-tls"}),
+	)
+	recorder := record.NewFakeRecorder(10)
+	s.recorder = recorder
+	ctx := context.Background()
+
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncImport(ctx, "frontend", "orphan"); err == nil {
+		t.Fatal("sync of an import without its export succeeded")
+	}
+	for _, want := range []string{
+		corev1.EventTypeNormal + " " + eventReasonSynced + " ",
+		corev1.EventTypeWarning + " " + eventReasonExportNotFound + " ",
+	} {
+		select {
+		case got := <-recorder.Events:
+			if !strings.HasPrefix(got, want) {
+				t.Errorf("got event %q, want it to start with %q", got, want)
+			}
+		default:
+			t.Errorf("no event starting with %q was recorded", want)
+		}
+	}
+	select {
+	case got := <-recorder.Events:
+		t.Errorf("got unexpected event %q", got)
+	default:
+	}
+}
+
+func TestEventReasonFor(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{err: fmt.Errorf("%w: backend/app", ErrExportNotFound), want: eventReasonExportNotFound},
+		{err: fmt.Errorf("%w: backend/app-tls", ErrSourceSecretMissing), want: eventReasonSourceSecretMissing},
+		{err: fmt.Errorf("%w: frontend", ErrNotAuthorized), want: eventReasonNotAuthorized},
+		{err: fmt.Errorf("%w: frontend/app-tls", ErrTargetNotManaged), want: eventReasonTargetNotManaged},
+		{err: errors.New("connection refused"), want: eventReasonSyncFailed},
+	}
+	for _, tt := range tests {
+		if got := eventReasonFor(tt.err); got != tt.want {
+			t.Errorf("eventReasonFor(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// clusterExportPrefix in fromExport selects a ClusterCertificateExport, e.g.
+// "cluster/root-ca".
+const clusterExportPrefix = "cluster/"
+
+// exportKind returns the kind and key of the export referenced by fromExport
+// from an import in defaultNS. Malformed references are reported as
+// ErrInvalidReference.
+func exportKind(defaultNS, ref string) (string, types.NamespacedName, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, clusterExportPrefix) {
+		name := strings.TrimSpace(strings.TrimPrefix(ref, clusterExportPrefix))
+		if name == "" || strings.Contains(name, "/") {
+			return "", types.NamespacedName{}, fmt.Errorf("%w: %q must be %s<name>", ErrInvalidReference, ref, clusterExportPrefix)
+		}
+		return "ClusterCertificateExport", types.NamespacedName{Name: name}, nil
+	}
+	key, err := parseNSName(defaultNS, ref)
+	if err != nil {
+		return "", types.NamespacedName{}, err
+	}
+	return "CertificateExport", key, nil
+}
+
+// exportID identifies an export across kinds in the importer index.
+func exportID(kind string, key types.NamespacedName) string {
+	return kind + " " + key.Namespace + "/" + key.Name
+}
+
+// indexImporters maps every export, by exportID, to the imports referencing
+// it through spec.fromExport or spec.fromExports, sorted by namespace/name.
+// Malformed references are skipped.
+func indexImporters(imports []unstructured.Unstructured) map[string][]types.NamespacedName {
+	index := map[string][]types.NamespacedName{}
+	for i := range imports {
+		imp := &imports[i]
+		impKey := types.NamespacedName{Namespace: imp.GetNamespace(), Name: imp.GetName()}
+		for _, ref := range importExportRefs(imp) {
+			kind, key, err := exportKind(imp.GetNamespace(), ref)
+			if err != nil {
+				continue
+			}
+			id := exportID(kind, key)
+			if !slices.Contains(index[id], impKey) {
+				index[id] = append(index[id], impKey)
+			}
+		}
+	}
+	for _, importers := range index {
+		slices.SortFunc(importers, func(a, b types.NamespacedName) int {
+			return strings.Compare(a.String(), b.String())
+		})
+	}
+	return index
+}
+
+// updateImporters records in status.importers of every export the imports
+// referencing it, as namespace/name.
+func (s *SyncController) updateImporters(ctx context.Context, exports, imports []unstructured.Unstructured) {
+	index := indexImporters(imports)
+	for i := range exports {
+		exp := &exports[i]
+		importers := index[exportID(exp.GetKind(), client.ObjectKeyFromObject(exp))]
+		names := make([]string, len(importers))
+		for j, imp := range importers {
+			names[j] = imp.String()
+		}
+		_ = s.updateStatus(ctx, exp, func(exp *unstructured.Unstructured) bool {
+			if slices.Equal(getStringSlice(exp.Object, "status.importers"), names) {
+				return false
+			}
+			if len(names) == 0 {
+				unstructured.RemoveNestedField(exp.Object, "status", "importers")
+				return true
+			}
+			_ = unstructured.SetNestedStringSlice(exp.Object, names, "status", "importers")
+			return true
+		})
+	}
+}
+
+// checkExportRefs validates every export reference of imp and reflects the
+// outcome in its InvalidReference condition.
+func (s *SyncController) checkExportRefs(ctx context.Context, imp *unstructured.Unstructured) error {
+	var refErr error
+	reason := reasonMalformedReference
+	for _, ref := range importExportRefs(imp) {
+		kind, key, err := exportKind(imp.GetNamespace(), ref)
+		if err == nil && s.opts.Namespace != "" && (kind == "ClusterCertificateExport" || key.Namespace != imp.GetNamespace()) {
+			err = fmt.Errorf("%w: %q refers to another namespace, which is not allowed while the controller is restricted to namespace %s (--namespace); reference an export in %s instead", ErrInvalidReference, ref, s.opts.Namespace, imp.GetNamespace())
+			reason = reasonCrossNamespaceReference
+		}
+		if err != nil {
+			refErr = fmt.Errorf("import %s/%s: %w", imp.GetNamespace(), imp.GetName(), err)
+			break
+		}
+	}
+	_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		if refErr == nil {
+			return removeCondition(imp, conditionInvalidReference)
+		}
+		return setCondition(imp, conditionInvalidReference, metav1.ConditionTrue, reason, refErr.Error())
+	})
+	return refErr
+}
+
+// getExport fetches the CertificateExport or ClusterCertificateExport
+// referenced by fromExport from an import in defaultNS.
+func getExport(ctx context.Context, r client.Reader, defaultNS, ref string) (*unstructured.Unstructured, error) {
+	kind, key, err := exportKind(defaultNS, ref)
+	if err != nil {
+		return nil, err
+	}
+	exp := &unstructured.Unstructured{}
+	exp.SetGroupVersionKind(schemaGVK(kind))
+	if err := r.Get(ctx, key, exp); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s %s: %w", ErrExportNotFound, kind, ref, err)
+		}
+		return nil, err
+	}
+	return exp, nil
+}
+
+// exportSource returns the key of the source secret of an export. Namespaced
+// exports reference a secret in their own namespace, cluster exports name the
+// source namespace explicitly.
+func exportSource(exp *unstructured.Unstructured) types.NamespacedName {
+	ns := exp.GetNamespace()
+	if exp.GetKind() == "ClusterCertificateExport" {
+		ns = getString(exp.Object, "spec.sourceNamespace")
+	}
+	return types.NamespacedName{Namespace: ns, Name: getString(exp.Object, "spec.secretRef")}
+}
+
+// checkSourceType rejects source secrets of an export that are not of type
+// kubernetes.io/tls or kubernetes.io/dockerconfigjson, unless the export sets
+// spec.allowOpaque and the source is Opaque.
+func checkSourceType(exp *unstructured.Unstructured, src *corev1.Secret) error {
+	allowOpaque := getBool(exp.Object, "spec.allowOpaque", false)
+	switch {
+	case src.Type == corev1.SecretTypeTLS, src.Type == corev1.SecretTypeDockerConfigJson:
+		return nil
+	case allowOpaque && src.Type == corev1.SecretTypeOpaque:
+		return nil
+	case allowOpaque:
+		return fmt.Errorf("%w: source secret %s/%s must be type kubernetes.io/tls, kubernetes.io/dockerconfigjson or Opaque", ErrWrongSecretType, src.Namespace, src.Name)
+	}
+	return fmt.Errorf("%w: source secret %s/%s must be type kubernetes.io/tls or kubernetes.io/dockerconfigjson", ErrWrongSecretType, src.Namespace, src.Name)
+}
+
+// importAllowed reports whether imports in namespace may copy from exp. An
+// export without spec.allowedNamespaces and spec.allowedNamespaceSelector
+// allows every namespace; imports in the export's own namespace are always
+// allowed.
+func importAllowed(ctx context.Context, r client.Reader, exp *unstructured.Unstructured, namespace string) (bool, error) {
+	if exp.GetKind() != "CertificateExport" || namespace == exp.GetNamespace() {
+		return true, nil
+	}
+	allowed := getStringSlice(exp.Object, "spec.allowedNamespaces")
+	raw, hasSelector, _ := unstructured.NestedMap(exp.Object, "spec", "allowedNamespaceSelector")
+	if len(allowed) == 0 && !hasSelector {
+		return true, nil
+	}
+	if slices.Contains(allowed, namespace) {
+		return true, nil
+	}
+	if !hasSelector {
+		return false, nil
+	}
+	var ls metav1.LabelSelector
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &ls); err != nil {
+		return false, fmt.Errorf("invalid allowedNamespaceSelector of export %s/%s: %w", exp.GetNamespace(), exp.GetName(), err)
+	}
+	sel, err := metav1.LabelSelectorAsSelector(&ls)
+	if err != nil {
+		return false, fmt.Errorf("invalid allowedNamespaceSelector of export %s/%s: %w", exp.GetNamespace(), exp.GetName(), err)
+	}
+	var ns corev1.Namespace
+	if err := r.Get(ctx, types.NamespacedName{Name: namespace}, &ns); err != nil {
+		return false, err
+	}
+	return sel.Matches(labels.Set(ns.Labels)), nil
+}
+
+// authorizeImport checks that imp may copy from exp. A denied import gets a
+// NotAuthorized condition and an error wrapping ErrNotAuthorized.
+func (s *SyncController) authorizeImport(ctx context.Context, imp, exp *unstructured.Unstructured) error {
+	ok, err := importAllowed(ctx, s, exp, imp.GetNamespace())
+	if err != nil {
+		return err
+	}
+	if ok {
+		return nil
+	}
+	err = fmt.Errorf("%w: export %s/%s does not allow imports from namespace %s", ErrNotAuthorized, exp.GetNamespace(), exp.GetName(), imp.GetNamespace())
+	_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		return setCondition(imp, conditionNotAuthorized, metav1.ConditionTrue, reasonNamespaceNotAllowed, err.Error())
+	})
+	return err
+}
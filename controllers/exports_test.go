@@ -0,0 +1,155 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+func TestExportKind(t *testing.T) {
+	tests := []struct {
+		ref      string
+		wantKind string
+		wantKey  types.NamespacedName
+		wantErr  bool
+	}{
+		{ref: "backend/app", wantKind: "CertificateExport", wantKey: types.NamespacedName{Namespace: "backend", Name: "app"}},
+		{ref: "cluster/root-ca", wantKind: "ClusterCertificateExport", wantKey: types.NamespacedName{Name: "root-ca"}},
+		{ref: " cluster/ root-ca ", wantKind: "ClusterCertificateExport", wantKey: types.NamespacedName{Name: "root-ca"}},
+		{ref: "cluster/", wantErr: true},
+		{ref: "cluster/root-ca/extra", wantErr: true},
+		{ref: "backend/", wantErr: true},
+	}
+	for _, tt := range tests {
+		kind, key, err := exportKind("frontend", tt.ref)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidReference) {
+				t.Errorf("exportKind(%q) = %s %v, %v, want %v", tt.ref, kind, key, err, ErrInvalidReference)
+			}
+			continue
+		}
+		if err != nil || kind != tt.wantKind || key != tt.wantKey {
+			t.Errorf("exportKind(%q) = %s %v, %v, want %s %v", tt.ref, kind, key, err, tt.wantKind, tt.wantKey)
+		}
+	}
+}
+
+func TestSyncImportMalformedReference(t *testing.T) {
+	s, c := newTestController(t, Options{},
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/", "targetSecret": "app-tls"}))
+	err := s.syncImport(context.Background(), "frontend", "app")
+	if !errors.Is(err, ErrInvalidReference) {
+		t.Fatalf("got error %v, want %v", err, ErrInvalidReference)
+	}
+	cond := getImportCondition(t, c, "frontend", "app", conditionInvalidReference)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonMalformedReference {
+		t.Errorf("got InvalidReference condition %+v, want True/%s", cond, reasonMalformedReference)
+	}
+}
+
+func TestSyncImportAllowedNamespaces(t *testing.T) {
+	tests := []struct {
+		name      string
+		allowed   []interface{}
+		selector  map[string]interface{}
+		namespace string
+		wantDeny  bool
+	}{
+		{name: "no allowlist", namespace: "frontend"},
+		{name: "listed", allowed: []interface{}{"frontend"}, namespace: "frontend"},
+		{name: "not listed", allowed: []interface{}{"api"}, namespace: "frontend", wantDeny: true},
+		{name: "own namespace", allowed: []interface{}{"api"}, namespace: "backend"},
+		{name: "selector matches", selector: map[string]interface{}{"matchLabels": map[string]interface{}{"team": "web"}}, namespace: "frontend"},
+		{name: "selector does not match", selector: map[string]interface{}{"matchLabels": map[string]interface{}{"team": "data"}}, namespace: "frontend", wantDeny: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crt, key := newKeyPair(t, "app")
+			exp := newExport("backend", "app", "app-tls")
+			if tt.allowed != nil {
+				_ = unstructured.SetNestedSlice(exp.Object, tt.allowed, "spec", "allowedNamespaces")
+			}
+			if tt.selector != nil {
+				_ = unstructured.SetNestedMap(exp.Object, tt.selector, "spec", "allowedNamespaceSelector")
+			}
+			s, c := newTestController(t, Options{},
+				&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "frontend", Labels: map[string]string{"team": "web"}}},
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+				exp,
+				newImport(tt.namespace, "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-copy"}),
+			)
+			err := s.syncImport(context.Background(), tt.namespace, "app")
+			cond := getImportCondition(t, c, tt.namespace, "app", conditionNotAuthorized)
+			if !tt.wantDeny {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if getSecret(t, c, tt.namespace, "app-copy") == nil {
+					t.Error("an allowed import did not write its target")
+				}
+				if cond != nil {
+					t.Errorf("got NotAuthorized condition %+v on an allowed import", cond)
+				}
+				return
+			}
+			if !errors.Is(err, ErrNotAuthorized) {
+				t.Fatalf("got error %v, want %v", err, ErrNotAuthorized)
+			}
+			if getSecret(t, c, tt.namespace, "app-copy") != nil {
+				t.Error("a denied import wrote its target")
+			}
+			if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonNamespaceNotAllowed {
+				t.Errorf("got NotAuthorized condition %+v, want True/%s", cond, reasonNamespaceNotAllowed)
+			}
+		})
+	}
+}
+
+func TestSyncImportAuthorizationRevoked(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	exp := newExport("backend", "app", "app-tls")
+	_ = unstructured.SetNestedSlice(exp.Object, []interface{}{"api"}, "spec", "allowedNamespaces")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		exp,
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx := context.Background()
+	if err := s.syncImport(ctx, "frontend", "app"); !errors.Is(err, ErrNotAuthorized) {
+		t.Fatalf("got error %v, want %v", err, ErrNotAuthorized)
+	}
+
+	// allowing the namespace clears the condition on the next sync
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "backend", Name: "app"}, exp); err != nil {
+		t.Fatal(err)
+	}
+	_ = unstructured.SetNestedSlice(exp.Object, []interface{}{"api", "frontend"}, "spec", "allowedNamespaces")
+	if err := c.Update(ctx, exp); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if cond := getImportCondition(t, c, "frontend", "app", conditionNotAuthorized); cond != nil {
+		t.Errorf("got NotAuthorized condition %+v after the namespace was allowed", cond)
+	}
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// cleanupFinalizer is set on every CertificateImport so its managed target
+// secret is deleted before the import goes away, and likewise on every
+// CertificateExport that pushes, for its pushed secrets.
+const cleanupFinalizer = "cert-trust.flolive.io/cleanup"
+
+// reconcileImportFinalizers adds the cleanup finalizer to live imports and
+// finishes deletion of imports that are being deleted. It returns the imports
+// that are still live and should be scheduled.
+func (s *SyncController) reconcileImportFinalizers(ctx context.Context, items []unstructured.Unstructured) []unstructured.Unstructured {
+	live := make([]unstructured.Unstructured, 0, len(items))
+	for i := range items {
+		item := &items[i]
+		logger := log.FromContext(ctx).WithValues("import", fmt.Sprintf("%s/%s", item.GetNamespace(), item.GetName()))
+		if item.GetDeletionTimestamp().IsZero() {
+			if controllerutil.AddFinalizer(item, cleanupFinalizer) {
+				if err := s.Update(ctx, item); err != nil {
+					logger.Error(err, "failed to add finalizer")
+				}
+			}
+			live = append(live, *item)
+			continue
+		}
+		if !controllerutil.ContainsFinalizer(item, cleanupFinalizer) {
+			continue
+		}
+		if err := s.cleanupImport(ctx, item); err != nil {
+			logger.Error(err, "failed to clean up target secret")
+			continue
+		}
+		controllerutil.RemoveFinalizer(item, cleanupFinalizer)
+		if err := s.Update(ctx, item); err != nil {
+			logger.Error(err, "failed to remove finalizer")
+			continue
+		}
+		certExpiry.DeleteLabelValues(item.GetNamespace(), item.GetName())
+		s.forgetRetries(item.GetNamespace() + "/" + item.GetName())
+		logger.Info("import cleanup completed")
+	}
+	return live
+}
+
+// reconcileExportFinalizers adds the cleanup finalizer to live exports that
+// push and finishes deletion of exports that are being deleted. An export
+// that stopped pushing has its pushed secrets deleted and loses the
+// finalizer. It returns the exports that are still live.
+func (s *SyncController) reconcileExportFinalizers(ctx context.Context, items []unstructured.Unstructured) []unstructured.Unstructured {
+	live := make([]unstructured.Unstructured, 0, len(items))
+	for i := range items {
+		item := &items[i]
+		logger := log.FromContext(ctx).WithValues("export", fmt.Sprintf("%s/%s", item.GetNamespace(), item.GetName()))
+		deleting := !item.GetDeletionTimestamp().IsZero()
+		if !deleting && getString(item.Object, "spec.targetSecret") != "" {
+			if controllerutil.AddFinalizer(item, cleanupFinalizer) {
+				if err := s.Update(ctx, item); err != nil {
+					logger.Error(err, "failed to add finalizer")
+				}
+			}
+			live = append(live, *item)
+			continue
+		}
+		if !deleting {
+			live = append(live, *item)
+		}
+		if !controllerutil.ContainsFinalizer(item, cleanupFinalizer) {
+			continue
+		}
+		if err := s.cleanupExport(ctx, item); err != nil {
+			logger.Error(err, "failed to clean up pushed secrets")
+			continue
+		}
+		controllerutil.RemoveFinalizer(item, cleanupFinalizer)
+		if err := s.Update(ctx, item); err != nil {
+			logger.Error(err, "failed to remove finalizer")
+			continue
+		}
+		logger.Info("export cleanup completed")
+	}
+	return live
+}
+
+// cleanupImport deletes the target secret or configmap of imp, but only when
+// it is marked as managed by this import. A missing target is not an error.
+func (s *SyncController) cleanupImport(ctx context.Context, imp *unstructured.Unstructured) error {
+	logger := log.FromContext(ctx).WithValues("import", fmt.Sprintf("%s/%s", imp.GetNamespace(), imp.GetName()))
+	var tgt client.Object
+	if name := getString(imp.Object, "spec.targetConfigMap"); name != "" {
+		tgt = &corev1.ConfigMap{}
+		tgt.SetName(name)
+	} else if name := getString(imp.Object, "spec.targetSecret"); name != "" {
+		tgt = &corev1.Secret{}
+		tgt.SetName(name)
+	} else {
+		return nil
+	}
+	target := importTarget(imp)
+	if err := s.Get(ctx, types.NamespacedName{Namespace: imp.GetNamespace(), Name: tgt.GetName()}, tgt); err != nil {
+		if apierrors.IsNotFound(err) {
+			logger.Info("target already deleted", "target", target)
+			return nil
+		}
+		return err
+	}
+	owner := fmt.Sprintf("%s/%s", imp.GetNamespace(), imp.GetName())
+	if managedBy := tgt.GetAnnotations()[managedByAnnotation]; managedBy != owner {
+		logger.Info("target not managed by this import, leaving it in place", "target", target, "managedBy", managedBy)
+		return nil
+	}
+	if err := s.Delete(ctx, tgt); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	logger.Info("deleted target", "target", target)
+	return nil
+}
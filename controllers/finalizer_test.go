@@ -0,0 +1,130 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+func TestReconcileImportFinalizers(t *testing.T) {
+	// deleting returns the import frontend/app being deleted, holding the
+	// cleanup finalizer
+	deleting := func() *unstructured.Unstructured {
+		imp := newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"})
+		imp.SetFinalizers([]string{cleanupFinalizer})
+		now := metav1.NewTime(time.Now())
+		imp.SetDeletionTimestamp(&now)
+		return imp
+	}
+	target := func(managedBy string) *corev1.Secret {
+		tgt := newSecret("frontend", "app-tls", corev1.SecretTypeOpaque, map[string][]byte{"ca.crt": []byte("ca")})
+		if managedBy != "" {
+			tgt.Annotations = map[string]string{managedByAnnotation: managedBy}
+		}
+		return tgt
+	}
+
+	tests := []struct {
+		name           string
+		imp            *unstructured.Unstructured
+		target         *corev1.Secret
+		wantLive       bool
+		wantTarget     bool
+		wantFinalizer  bool
+		wantImportGone bool
+	}{
+		{
+			name:          "adds the finalizer to a live import",
+			imp:           newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+			target:        target("frontend/app"),
+			wantLive:      true,
+			wantTarget:    true,
+			wantFinalizer: true,
+		},
+		{
+			name:           "deletes the managed target",
+			imp:            deleting(),
+			target:         target("frontend/app"),
+			wantImportGone: true,
+		},
+		{
+			name:           "finishes when the target is already deleted",
+			imp:            deleting(),
+			wantImportGone: true,
+		},
+		{
+			name:           "keeps a target not managed by the import",
+			imp:            deleting(),
+			target:         target(""),
+			wantTarget:     true,
+			wantImportGone: true,
+		},
+		{
+			name:           "keeps a target managed by another import",
+			imp:            deleting(),
+			target:         target("frontend/other"),
+			wantTarget:     true,
+			wantImportGone: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{tt.imp}
+			if tt.target != nil {
+				objs = append(objs, tt.target)
+			}
+			s, c := newTestController(t, Options{}, objs...)
+			ctx := context.Background()
+
+			var imp unstructured.Unstructured
+			imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+			key := types.NamespacedName{Namespace: "frontend", Name: "app"}
+			if err := c.Get(ctx, key, &imp); err != nil {
+				t.Fatal(err)
+			}
+			live := s.reconcileImportFinalizers(ctx, []unstructured.Unstructured{imp})
+			if got := len(live) == 1; got != tt.wantLive {
+				t.Errorf("got live %v, want %v", got, tt.wantLive)
+			}
+			if got := getSecret(t, c, "frontend", "app-tls") != nil; got != tt.wantTarget {
+				t.Errorf("got target present %v, want %v", got, tt.wantTarget)
+			}
+
+			err := c.Get(ctx, key, &imp)
+			if tt.wantImportGone {
+				if !apierrors.IsNotFound(err) {
+					t.Fatalf("import still exists after its cleanup (error %v)", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := controllerutil.ContainsFinalizer(&imp, cleanupFinalizer); got != tt.wantFinalizer {
+				t.Errorf("got finalizer %v, want %v", got, tt.wantFinalizer)
+			}
+		})
+	}
+}
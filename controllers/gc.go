@@ -0,0 +1,86 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// collectOrphans deletes managed target secrets whose import no longer
+// exists, e.g. because it was deleted while the controller was down and its
+// finalizer was removed by hand, and likewise pushed secrets whose export no
+// longer exists. imports and exports are the ones listed on this pass; a
+// secret whose owner is missing from them is only deleted once a live read
+// confirms the owner is gone, so an object created since the list keeps its
+// secrets. Secrets in namespaces outside the controller's scope are left
+// alone.
+func (s *SyncController) collectOrphans(ctx context.Context, imports, exports []unstructured.Unstructured) {
+	logger := log.FromContext(ctx)
+	known := make(map[string]bool, len(imports))
+	for i := range imports {
+		known[imports[i].GetNamespace()+"/"+imports[i].GetName()] = true
+	}
+	knownExports := make(map[string]bool, len(exports))
+	for i := range exports {
+		knownExports[exports[i].GetNamespace()+"/"+exports[i].GetName()] = true
+	}
+	var secrets corev1.SecretList
+	if err := s.List(ctx, &secrets, client.InNamespace(s.opts.Namespace)); err != nil {
+		logger.Error(err, "failed to list secrets for orphan collection")
+		return
+	}
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if !s.namespaceAllowed(secret.Namespace) {
+			continue
+		}
+		if managedBy := secret.Annotations[managedByAnnotation]; managedBy != "" && !known[managedBy] {
+			s.collectOrphan(ctx, secret, "CertificateImport", managedBy)
+		}
+		if pushedBy := secret.Annotations[pushedByAnnotation]; pushedBy != "" && !knownExports[pushedBy] {
+			s.collectOrphan(ctx, secret, "CertificateExport", pushedBy)
+		}
+	}
+}
+
+// collectOrphan deletes secret once a live read confirms that owner, the
+// namespace/name of the kind of object that wrote it, is gone.
+func (s *SyncController) collectOrphan(ctx context.Context, secret *corev1.Secret, kind, owner string) {
+	logger := log.FromContext(ctx).WithValues("secret", client.ObjectKeyFromObject(secret).String(), "kind", kind, "owner", owner)
+	key, err := parseNSName(secret.Namespace, owner)
+	if err != nil {
+		return
+	}
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schemaGVK(kind))
+	if err := s.liveReader().Get(ctx, key, obj); !apierrors.IsNotFound(err) {
+		if err != nil {
+			logger.Error(err, "failed to get owner of secret")
+		}
+		return
+	}
+	uid, rv := secret.UID, secret.ResourceVersion
+	if err := s.Delete(ctx, secret, client.Preconditions{UID: &uid, ResourceVersion: &rv}); err != nil && !apierrors.IsNotFound(err) {
+		logger.Error(err, "failed to delete orphaned secret")
+		return
+	}
+	logger.Info("deleted orphaned secret of a deleted owner")
+}
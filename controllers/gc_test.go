@@ -0,0 +1,68 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestCollectOrphans(t *testing.T) {
+	managed := func(namespace, name, managedBy string) *corev1.Secret {
+		sec := newSecret(namespace, name, corev1.SecretTypeOpaque, map[string][]byte{"ca.crt": []byte("ca")})
+		if managedBy != "" {
+			sec.Annotations = map[string]string{managedByAnnotation: managedBy}
+		}
+		return sec
+	}
+	live := newImport("frontend", "live", map[string]interface{}{"fromExport": "backend/app"})
+	// created after the imports were listed for this pass
+	late := newImport("frontend", "late", map[string]interface{}{"fromExport": "backend/app"})
+	exp := newExport("backend", "ca", "ca-tls")
+	s, c := newTestController(t, Options{ExcludeNamespaces: []string{"kube-system"}},
+		live, late, exp,
+		managed("frontend", "live-tls", "frontend/live"),
+		managed("frontend", "late-tls", "frontend/late"),
+		managed("frontend", "gone-tls", "frontend/gone"),
+		managed("frontend", "other-ns-tls", "other/gone"),
+		managed("frontend", "unmanaged", ""),
+		managed("kube-system", "gone-tls", "kube-system/gone"),
+		newPushedSecret("api", "ca-tls", "backend/ca"),
+		newPushedSecret("api", "old-tls", "backend/old"),
+	)
+	s.collectOrphans(context.Background(), []unstructured.Unstructured{*live}, []unstructured.Unstructured{*exp})
+
+	tests := []struct {
+		namespace, name string
+		kept            bool
+	}{
+		{namespace: "frontend", name: "live-tls", kept: true},
+		{namespace: "frontend", name: "late-tls", kept: true},
+		{namespace: "frontend", name: "gone-tls"},
+		{namespace: "frontend", name: "other-ns-tls"},
+		{namespace: "frontend", name: "unmanaged", kept: true},
+		{namespace: "kube-system", name: "gone-tls", kept: true},
+		{namespace: "api", name: "ca-tls", kept: true},
+		{namespace: "api", name: "old-tls"},
+	}
+	for _, tt := range tests {
+		if kept := getSecret(t, c, tt.namespace, tt.name) != nil; kept != tt.kept {
+			t.Errorf("%s/%s: got kept %t, want %t", tt.namespace, tt.name, kept, tt.kept)
+		}
+	}
+}
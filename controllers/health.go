@@ -0,0 +1,82 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+)
+
+// rescheduleStaleAfter is how many reschedule intervals may pass without the
+// reschedule loop completing a pass before the liveness check fails.
+const rescheduleStaleAfter = 5
+
+// markRescheduled records that the reschedule loop completed a pass, or
+// started, at now.
+func (s *SyncController) markRescheduled(now time.Time) {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	s.lastRebuildTime = now
+}
+
+// rescheduleAlive returns a liveness check that fails when the reschedule
+// loop has not completed a pass within rescheduleStaleAfter intervals, e.g.
+// because it is stuck on the API server or exited. It passes before the loop
+// starts, which on replicas that are not the leader is never.
+func (s *SyncController) rescheduleAlive() healthz.Checker {
+	return func(_ *http.Request) error {
+		s.healthMu.Lock()
+		last := s.lastRebuildTime
+		s.healthMu.Unlock()
+		if last.IsZero() {
+			return nil
+		}
+		staleAfter := rescheduleStaleAfter * s.rescheduleInterval()
+		if age := time.Since(last); age > staleAfter {
+			return fmt.Errorf("reschedule loop last completed %s ago, more than %s", age.Round(time.Second), staleAfter)
+		}
+		return nil
+	}
+}
+
+// markSchedulesBuilt records that buildSchedules completed successfully.
+func (s *SyncController) markSchedulesBuilt() {
+	s.healthMu.Lock()
+	defer s.healthMu.Unlock()
+	s.schedulesBuilt = true
+}
+
+// schedulesReady returns a readiness check that fails until schedules were
+// built once. Replicas that are not the leader never build schedules, so
+// they are ready as long as they wait for the election.
+func (s *SyncController) schedulesReady(elected <-chan struct{}) healthz.Checker {
+	return func(_ *http.Request) error {
+		select {
+		case <-elected:
+		default:
+			return nil
+		}
+		s.healthMu.Lock()
+		defer s.healthMu.Unlock()
+		if !s.schedulesBuilt {
+			return errors.New("schedules not built yet")
+		}
+		return nil
+	}
+}
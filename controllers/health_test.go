@@ -0,0 +1,107 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestSchedulesReady(t *testing.T) {
+	s, _ := newTestController(t, Options{})
+	elected := make(chan struct{})
+	ready := s.schedulesReady(elected)
+	if err := ready(nil); err != nil {
+		t.Errorf("a replica waiting for the election is not ready: %v", err)
+	}
+	close(elected)
+	if err := ready(nil); err == nil {
+		t.Error("the leader is ready before building schedules")
+	}
+	s.markSchedulesBuilt()
+	if err := ready(nil); err != nil {
+		t.Errorf("the leader is not ready after building schedules: %v", err)
+	}
+}
+
+func TestRescheduleLoopReadiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		listErr   error
+		wantReady bool
+	}{
+		{name: "schedules built", wantReady: true},
+		{name: "listing exports fails", listErr: errors.New("etcdserver: request timed out")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			passes := make(chan struct{}, 10)
+			s, _ := newInterceptedTestController(t, Options{}, interceptor.Funcs{
+				List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+					select {
+					case passes <- struct{}{}:
+					default:
+					}
+					if tt.listErr != nil {
+						return tt.listErr
+					}
+					return c.List(ctx, list, opts...)
+				},
+			})
+			elected := make(chan struct{})
+			close(elected)
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go s.rescheduleLoop(ctx)
+			<-passes
+			if tt.wantReady {
+				eventually(t, "the leader is ready", func() bool { return s.schedulesReady(elected)(nil) == nil })
+				return
+			}
+			// the pass fails on its first list and retries after seconds
+			if err := s.schedulesReady(elected)(nil); err == nil {
+				t.Error("the leader is ready although building schedules failed")
+			}
+		})
+	}
+}
+
+func TestRescheduleAlive(t *testing.T) {
+	s, _ := newTestController(t, Options{RescheduleInterval: time.Minute})
+	alive := s.rescheduleAlive()
+	if err := alive(nil); err != nil {
+		t.Errorf("failed before the reschedule loop started: %v", err)
+	}
+	tests := []struct {
+		name    string
+		age     time.Duration
+		wantErr bool
+	}{
+		{name: "just completed"},
+		{name: "within the limit", age: rescheduleStaleAfter*time.Minute - time.Second},
+		{name: "stuck", age: rescheduleStaleAfter*time.Minute + time.Second, wantErr: true},
+	}
+	for _, tt := range tests {
+		s.markRescheduled(time.Now().Add(-tt.age))
+		if err := alive(nil); (err != nil) != tt.wantErr {
+			t.Errorf("%s: got error %v, want error %t", tt.name, err, tt.wantErr)
+		}
+	}
+}
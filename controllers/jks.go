@@ -0,0 +1,83 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"crypto/x509"
+	"fmt"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// defaultJKSKey is the key of the keystore in the target secret when
+	// spec.jks.key is unset.
+	defaultJKSKey = "keystore.jks"
+	// defaultJKSAlias is the alias of the private key entry when
+	// spec.jks.alias is unset.
+	defaultJKSAlias = "certificate"
+)
+
+// encodeJKS encodes the key pair in src, with its tls.crt chain, as a
+// private key entry under spec.alias of a Java KeyStore, and each ca.crt
+// certificate as a trusted entry aliased ca, ca-1, ca-2, and so on. Without
+// a key pair the keystore only holds the trusted entries.
+func encodeJKS(spec map[string]interface{}, src map[string][]byte, password string) ([]byte, error) {
+	caCerts, err := parseCertificates(src["ca.crt"])
+	if err != nil {
+		return nil, err
+	}
+	ks := keystore.New(keystore.WithOrderedAliases())
+	if secretTypeFor(src) == corev1.SecretTypeTLS {
+		key, chain, err := keyPairChain(src)
+		if err != nil {
+			return nil, err
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, err
+		}
+		entry := keystore.PrivateKeyEntry{CreationTime: chain[0].NotBefore, PrivateKey: der}
+		for _, crt := range chain {
+			entry.CertificateChain = append(entry.CertificateChain, keystore.Certificate{Type: "X509", Content: crt.Raw})
+		}
+		alias := getString(spec, "alias")
+		if alias == "" {
+			alias = defaultJKSAlias
+		}
+		if err := ks.SetPrivateKeyEntry(alias, entry, []byte(password)); err != nil {
+			return nil, err
+		}
+	} else if len(caCerts) == 0 {
+		return nil, fmt.Errorf("no key pair or CA certificates to store")
+	}
+	for i, crt := range caCerts {
+		alias := "ca"
+		if i > 0 {
+			alias = fmt.Sprintf("ca-%d", i)
+		}
+		entry := keystore.TrustedCertificateEntry{CreationTime: crt.NotBefore, Certificate: keystore.Certificate{Type: "X509", Content: crt.Raw}}
+		if err := ks.SetTrustedCertificateEntry(alias, entry); err != nil {
+			return nil, err
+		}
+	}
+	var buf bytes.Buffer
+	if err := ks.Store(&buf, []byte(password)); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/x509"
+	"encoding/pem"
+	"testing"
+
+	"github.com/pavlo-v-chernykh/keystore-go/v4"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSyncImportJKS(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	ca, _ := newKeyPair(t, "ca")
+	leafDER, _ := pem.Decode(crt)
+	caDER, _ := pem.Decode(ca)
+
+	tests := []struct {
+		name      string
+		jks       map[string]interface{}
+		wantKey   string
+		wantAlias string
+	}{
+		{
+			name:      "default key and alias",
+			jks:       map[string]interface{}{"passwordSecretRef": map[string]interface{}{"name": "jks-pass"}},
+			wantKey:   defaultJKSKey,
+			wantAlias: defaultJKSAlias,
+		},
+		{
+			name: "configured key and alias",
+			jks: map[string]interface{}{
+				"key":               "truststore.jks",
+				"alias":             "web",
+				"passwordSecretRef": map[string]interface{}{"name": "jks-pass", "key": "storepass"},
+			},
+			wantKey:   "truststore.jks",
+			wantAlias: "web",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestController(t, Options{},
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key, "ca.crt": ca}),
+				newExport("backend", "app", "app-tls"),
+				newSecret("frontend", "jks-pass", corev1.SecretTypeOpaque, map[string][]byte{"password": []byte("changeit"), "storepass": []byte("changeit")}),
+				newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls", "jks": tt.jks}),
+			)
+			if err := s.syncImport(context.Background(), "frontend", "app"); err != nil {
+				t.Fatal(err)
+			}
+			raw := getSecret(t, c, "frontend", "app-tls").Data[tt.wantKey]
+			if len(raw) == 0 {
+				t.Fatalf("target has no %s", tt.wantKey)
+			}
+
+			if err := keystore.New().Load(bytes.NewReader(raw), []byte("wrong")); err == nil {
+				t.Error("keystore loads with the wrong password")
+			}
+			ks := keystore.New()
+			if err := ks.Load(bytes.NewReader(raw), []byte("changeit")); err != nil {
+				t.Fatal(err)
+			}
+			if !ks.IsPrivateKeyEntry(tt.wantAlias) {
+				t.Fatalf("no private key entry %q, got aliases %v", tt.wantAlias, ks.Aliases())
+			}
+			entry, err := ks.GetPrivateKeyEntry(tt.wantAlias, []byte("changeit"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entry.CertificateChain) != 1 || !bytes.Equal(entry.CertificateChain[0].Content, leafDER.Bytes) {
+				t.Error("private key entry chain is not the source tls.crt")
+			}
+			if _, err := x509.ParsePKCS8PrivateKey(entry.PrivateKey); err != nil {
+				t.Errorf("private key entry does not hold a PKCS#8 key: %v", err)
+			}
+			trusted, err := ks.GetTrustedCertificateEntry("ca")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(trusted.Certificate.Content, caDER.Bytes) {
+				t.Error("trusted entry ca is not the source ca.crt")
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultPasswordKey is the key of a keystore password in the secret named by
+// passwordSecretRef when passwordSecretRef.key is unset.
+const defaultPasswordKey = "password"
+
+// keystoreFormat describes a keystore an import can add to its target secret,
+// configured by spec.<field>.
+type keystoreFormat struct {
+	field      string
+	defaultKey string
+	// checksumAnnotation holds a checksum of the inputs of the keystore on the
+	// target secret. Keystore encoding is randomized, so the keystore is only
+	// rebuilt when the checksum changes.
+	checksumAnnotation string
+	encode             func(spec map[string]interface{}, src map[string][]byte, password string) ([]byte, error)
+}
+
+var keystoreFormats = []keystoreFormat{
+	{field: "pkcs12", defaultKey: defaultPKCS12Key, checksumAnnotation: annotationPrefix + "pkcs12-checksum", encode: encodePKCS12},
+	{field: "jks", defaultKey: defaultJKSKey, checksumAnnotation: annotationPrefix + "jks-checksum", encode: encodeJKS},
+}
+
+// keystorePassword reads the password referenced by
+// spec.<field>.passwordSecretRef from a secret in the import's namespace.
+func (s *SyncController) keystorePassword(ctx context.Context, imp *unstructured.Unstructured, field string) (string, error) {
+	name := getString(imp.Object, "spec."+field+".passwordSecretRef.name")
+	if name == "" {
+		return "", fmt.Errorf("spec.%s.passwordSecretRef.name is required", field)
+	}
+	key := getString(imp.Object, "spec."+field+".passwordSecretRef.key")
+	if key == "" {
+		key = defaultPasswordKey
+	}
+	var sec corev1.Secret
+	if err := s.Get(ctx, types.NamespacedName{Namespace: imp.GetNamespace(), Name: name}, &sec); err != nil {
+		return "", fmt.Errorf("password secret of spec.%s: %w", field, err)
+	}
+	password, ok := sec.Data[key]
+	if !ok {
+		return "", fmt.Errorf("password secret %s/%s has no key %q", imp.GetNamespace(), name, key)
+	}
+	return string(password), nil
+}
+
+// addKeystores adds the keystores requested by the import to data, built
+// from the tls.crt, tls.key and ca.crt in src. The keystores of current, the
+// existing target secret if any, are kept while the checksum of their inputs
+// is unchanged. It returns the checksum annotations to record on the target;
+// annotations of keystores that are not requested are absent.
+func (s *SyncController) addKeystores(ctx context.Context, imp *unstructured.Unstructured, src, data map[string][]byte, current *corev1.Secret) (map[string]string, error) {
+	checksums := map[string]string{}
+	for _, f := range keystoreFormats {
+		spec, ok, _ := unstructured.NestedMap(imp.Object, "spec", f.field)
+		if !ok {
+			continue
+		}
+		key := getString(imp.Object, "spec."+f.field+".key")
+		if key == "" {
+			key = f.defaultKey
+		}
+		if _, ok := data[key]; ok {
+			return nil, fmt.Errorf("spec.%s key %q collides with another key of the target", f.field, key)
+		}
+		password, err := s.keystorePassword(ctx, imp, f.field)
+		if err != nil {
+			return nil, err
+		}
+		rawSpec, _ := json.Marshal(spec)
+		checksum := dataChecksum(map[string][]byte{
+			corev1.TLSCertKey:       src[corev1.TLSCertKey],
+			corev1.TLSPrivateKeyKey: src[corev1.TLSPrivateKeyKey],
+			"ca.crt":                src["ca.crt"],
+			"password":              []byte(password),
+			"spec":                  rawSpec,
+		})
+		checksums[f.checksumAnnotation] = checksum
+		if current != nil && current.Annotations[f.checksumAnnotation] == checksum {
+			if keystore, ok := current.Data[key]; ok {
+				data[key] = keystore
+				continue
+			}
+		}
+		keystore, err := f.encode(spec, src, password)
+		if err != nil {
+			return nil, fmt.Errorf("building spec.%s keystore: %w", f.field, err)
+		}
+		data[key] = keystore
+	}
+	return checksums, nil
+}
+
+// setKeystoreAnnotations records checksums on meta and removes the checksum
+// annotations of keystores that are no longer requested.
+func setKeystoreAnnotations(meta map[string]string, checksums map[string]string) {
+	for _, f := range keystoreFormats {
+		if checksum, ok := checksums[f.checksumAnnotation]; ok {
+			meta[f.checksumAnnotation] = checksum
+		} else {
+			delete(meta, f.checksumAnnotation)
+		}
+	}
+}
+
+// keyPairChain parses the key pair in src and the certificates of its
+// tls.crt chain, leaf first.
+func keyPairChain(src map[string][]byte) (crypto.PrivateKey, []*x509.Certificate, error) {
+	pair, err := tls.X509KeyPair(src[corev1.TLSCertKey], src[corev1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, nil, err
+	}
+	chain := make([]*x509.Certificate, 0, len(pair.Certificate))
+	for _, der := range pair.Certificate {
+		crt, err := x509.ParseCertificate(der)
+		if err != nil {
+			return nil, nil, err
+		}
+		chain = append(chain, crt)
+	}
+	return pair.PrivateKey, chain, nil
+}
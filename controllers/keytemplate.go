@@ -0,0 +1,59 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// keyTemplateData is what the Go templates in spec.keyMap values are
+// rendered with, e.g. {{ .ImportName }}-{{ .SourceKey }}.
+type keyTemplateData struct {
+	ImportName string
+	Namespace  string
+	SourceKey  string
+}
+
+// renderKeyMap renders the values of keyMap as Go templates for the import
+// namespace/name and checks that every resulting target key is a valid
+// secret key. Values without template actions are returned as is.
+func renderKeyMap(keyMap map[string]string, namespace, name string) (map[string]string, error) {
+	if len(keyMap) == 0 {
+		return keyMap, nil
+	}
+	out := make(map[string]string, len(keyMap))
+	for from, to := range keyMap {
+		if strings.Contains(to, "{{") {
+			tmpl, err := template.New(from).Option("missingkey=error").Parse(to)
+			if err != nil {
+				return nil, fmt.Errorf("keyMap template for key %q: %v", from, err)
+			}
+			var b strings.Builder
+			if err := tmpl.Execute(&b, keyTemplateData{ImportName: name, Namespace: namespace, SourceKey: from}); err != nil {
+				return nil, fmt.Errorf("keyMap template for key %q: %v", from, err)
+			}
+			to = b.String()
+		}
+		if errs := validation.IsConfigMapKey(to); len(errs) > 0 {
+			return nil, fmt.Errorf("keyMap maps key %q to invalid key %q: %s", from, to, strings.Join(errs, ", "))
+		}
+		out[from] = to
+	}
+	return out, nil
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+var (
+	// certExpiry exposes the notAfter of the leaf certificate mirrored by each
+	// CertificateImport so alerting can be driven from Prometheus.
+	certExpiry = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "certtrust_cert_expiry_timestamp_seconds",
+		Help: "Expiry (notAfter) of the leaf certificate mirrored by a CertificateImport, as a Unix timestamp.",
+	}, []string{"namespace", "name"})
+
+	// syncsInFlight counts the syncs currently running, to tune
+	// --max-concurrent-syncs.
+	syncsInFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "certtrust_syncs_in_flight",
+		Help: "Number of import syncs, export pushes and trust bundle publishes currently running.",
+	})
+
+	// importOverdue flags imports that missed a scheduled run, a
+	// dead-man's switch for schedules that silently stopped firing.
+	importOverdue = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "certtrust_import_overdue",
+		Help: "1 if a CertificateImport missed a whole scheduled run since its last successful sync, else 0.",
+	}, []string{"namespace", "name"})
+
+	// pendingSyncs counts syncs that were triggered but have not started,
+	// to spot lag between a source rotation and its propagation.
+	pendingSyncs = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "certtrust_pending_syncs",
+		Help: "Number of syncs waiting to start: queued immediate import syncs and syncs waiting for a free --max-concurrent-syncs slot.",
+	})
+
+	// cronEntries, scheduleRebuilds and scheduleRebuildsSkipped show the
+	// churn of the scheduler and whether the hash-based skip works.
+	cronEntries = prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "certtrust_cron_entries",
+		Help: "Number of active cron entries for imports and exports.",
+	})
+	scheduleRebuilds = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "certtrust_schedule_rebuilds_total",
+		Help: "Number of passes that rebuilt the schedules because imports or exports changed.",
+	})
+	scheduleRebuildsSkipped = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "certtrust_schedule_rebuild_skipped_total",
+		Help: "Number of passes that skipped rebuilding the schedules because nothing changed.",
+	})
+)
+
+func init() {
+	metrics.Registry.MustRegister(certExpiry, syncsInFlight, pendingSyncs, importOverdue, cronEntries, scheduleRebuilds, scheduleRebuildsSkipped)
+}
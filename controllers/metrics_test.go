@@ -0,0 +1,71 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestBuildSchedulesMetrics(t *testing.T) {
+	s, c := newTestController(t, Options{},
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "a", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "a-tls", "schedule": "0 * * * *"}),
+		newImport("frontend", "b", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "b-tls", "schedule": "30 * * * *"}),
+	)
+	ctx := context.Background()
+	rebuilds, skipped := testutil.ToFloat64(scheduleRebuilds), testutil.ToFloat64(scheduleRebuildsSkipped)
+
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(scheduleRebuilds) - rebuilds; got != 1 {
+		t.Errorf("got %v rebuilds after the first pass, want 1", got)
+	}
+	// both imports and the export
+	if got := testutil.ToFloat64(cronEntries); got != 3 {
+		t.Errorf("got %v cron entries, want 3", got)
+	}
+
+	// nothing changed
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(scheduleRebuildsSkipped) - skipped; got != 1 {
+		t.Errorf("got %v skipped rebuilds after an unchanged pass, want 1", got)
+	}
+	if got := testutil.ToFloat64(scheduleRebuilds) - rebuilds; got != 1 {
+		t.Errorf("got %v rebuilds after an unchanged pass, want still 1", got)
+	}
+
+	// a new import forces a rebuild
+	if err := c.Create(ctx, newImport("frontend", "c", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "c-tls", "schedule": "45 * * * *"})); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if got := testutil.ToFloat64(scheduleRebuilds) - rebuilds; got != 2 {
+		t.Errorf("got %v rebuilds after an import was added, want 2", got)
+	}
+	if got := testutil.ToFloat64(scheduleRebuildsSkipped) - skipped; got != 1 {
+		t.Errorf("got %v skipped rebuilds after an import was added, want still 1", got)
+	}
+	if got := testutil.ToFloat64(cronEntries); got != 4 {
+		t.Errorf("got %v cron entries, want 4", got)
+	}
+}
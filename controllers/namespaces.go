@@ -0,0 +1,52 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+	"slices"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// namespaceAllowed reports whether the controller may act in ns according to
+// Options.WatchNamespaces and Options.ExcludeNamespaces. An empty watch list
+// allows every namespace that is not excluded.
+func (s *SyncController) namespaceAllowed(ns string) bool {
+	if slices.Contains(s.opts.ExcludeNamespaces, ns) {
+		return false
+	}
+	return len(s.opts.WatchNamespaces) == 0 || slices.Contains(s.opts.WatchNamespaces, ns)
+}
+
+// checkNamespaceAllowed returns an error when the controller may not write
+// into ns.
+func (s *SyncController) checkNamespaceAllowed(ns string) error {
+	if !s.namespaceAllowed(ns) {
+		return fmt.Errorf("namespace %s is not watched by this controller (see --watch-namespaces and --exclude-namespaces)", ns)
+	}
+	return nil
+}
+
+// filterNamespaces drops items in namespaces the controller may not act in.
+func (s *SyncController) filterNamespaces(items []unstructured.Unstructured) []unstructured.Unstructured {
+	out := items[:0]
+	for i := range items {
+		if s.namespaceAllowed(items[i].GetNamespace()) {
+			out = append(out, items[i])
+		}
+	}
+	return out
+}
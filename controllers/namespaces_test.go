@@ -0,0 +1,93 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestNamespaceAllowed(t *testing.T) {
+	tests := []struct {
+		name string
+		opts Options
+		ns   string
+		want bool
+	}{
+		{name: "no lists", ns: "frontend", want: true},
+		{name: "watched", opts: Options{WatchNamespaces: []string{"frontend", "backend"}}, ns: "frontend", want: true},
+		{name: "not watched", opts: Options{WatchNamespaces: []string{"backend"}}, ns: "frontend"},
+		{name: "excluded", opts: Options{ExcludeNamespaces: []string{"frontend"}}, ns: "frontend"},
+		{name: "not excluded", opts: Options{ExcludeNamespaces: []string{"kube-system"}}, ns: "frontend", want: true},
+		{name: "watched and excluded", opts: Options{WatchNamespaces: []string{"frontend"}, ExcludeNamespaces: []string{"frontend"}}, ns: "frontend"},
+	}
+	for _, tt := range tests {
+		s, _ := newTestController(t, tt.opts)
+		if got := s.namespaceAllowed(tt.ns); got != tt.want {
+			t.Errorf("%s: got %t, want %t", tt.name, got, tt.want)
+		}
+		if err := s.checkNamespaceAllowed(tt.ns); (err == nil) != tt.want {
+			t.Errorf("%s: got error %v, want allowed %t", tt.name, err, tt.want)
+		}
+	}
+}
+
+func TestFilterNamespaces(t *testing.T) {
+	s, _ := newTestController(t, Options{WatchNamespaces: []string{"frontend", "api"}, ExcludeNamespaces: []string{"api"}})
+	items := []unstructured.Unstructured{
+		*newImport("frontend", "app", nil),
+		*newImport("api", "app", nil),
+		*newImport("web", "app", nil),
+	}
+	got := s.filterNamespaces(items)
+	if len(got) != 1 || got[0].GetNamespace() != "frontend" {
+		t.Errorf("got %d items, want only the one in frontend", len(got))
+	}
+}
+
+func TestSyncImportOutsideWatchedNamespace(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{ExcludeNamespaces: []string{"frontend"}},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	if err := s.syncImport(context.Background(), "frontend", "app"); err == nil {
+		t.Error("synced an import in an excluded namespace")
+	}
+	if getSecret(t, c, "frontend", "app-tls") != nil {
+		t.Error("wrote the target into an excluded namespace")
+	}
+}
+
+func TestSyncExportPushSkipsExcludedNamespaces(t *testing.T) {
+	crt, key := newKeyPair(t, "ca")
+	s, c := newTestController(t, Options{ExcludeNamespaces: []string{"kube-system"}},
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newPushExport("backend", "ca", "ca-tls", "ca-tls", "api", "kube-system"),
+	)
+	if err := s.syncExportPush(context.Background(), "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	if getSecret(t, c, "api", "ca-tls") == nil {
+		t.Error("did not push into a watched namespace")
+	}
+	if getSecret(t, c, "kube-system", "ca-tls") != nil {
+		t.Error("pushed into an excluded namespace")
+	}
+}
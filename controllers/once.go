@@ -0,0 +1,77 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// SyncResult is the outcome of syncing one import or export in SyncAll.
+type SyncResult struct {
+	Kind      string
+	Namespace string
+	Name      string
+	// Skipped is set for suspended objects, which are not synced
+	Skipped bool
+	Err     error
+}
+
+// SyncAll runs a single sync of every CertificateExport and then every
+// CertificateImport with c, outside of any manager, e.g. in a Job. Exports
+// with spec.targetSecret are pushed, all others validated. It returns the
+// result of every object; the error is only set when listing them fails.
+// Events are not recorded.
+func SyncAll(ctx context.Context, c client.Client, scheme *runtime.Scheme, opts Options) ([]SyncResult, error) {
+	if opts.Namespace != "" {
+		opts.WatchNamespaces = []string{opts.Namespace}
+	}
+	c = newRateLimitedClient(c, opts.WriteQPS, opts.WriteBurst)
+	if opts.DryRun {
+		c = newDryRunClient(c)
+	}
+	s := NewSyncController(c, nil, scheme, nil, opts)
+
+	var results []SyncResult
+	for _, kind := range []string{"CertificateExport", "CertificateImport"} {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schemaGVKList(kind))
+		if err := c.List(ctx, list, client.InNamespace(opts.Namespace)); err != nil {
+			return nil, err
+		}
+		items := s.filterNamespaces(list.Items)
+		sortByNamespacedName(items)
+		for i := range items {
+			item := &items[i]
+			ns, name := item.GetNamespace(), item.GetName()
+			result := SyncResult{Kind: kind, Namespace: ns, Name: name}
+			switch {
+			case isSuspended(item):
+				result.Skipped = true
+			case kind == "CertificateImport":
+				result.Err = s.syncImport(ctx, ns, name)
+			case getString(item.Object, "spec.targetSecret") != "":
+				result.Err = s.syncExportPush(ctx, ns, name)
+			default:
+				result.Err = s.syncExport(ctx, ns, name)
+			}
+			results = append(results, result)
+		}
+	}
+	return results, nil
+}
@@ -0,0 +1,61 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestSyncAll(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	paused := newExport("backend", "paused", "app-tls")
+	_ = unstructured.SetNestedField(paused.Object, true, "spec", "suspend")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		paused,
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("frontend", "broken", map[string]interface{}{"fromExport": "backend/missing", "targetSecret": "broken-tls"}),
+	)
+	results, err := SyncAll(context.Background(), c, s.scheme, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	type outcome struct {
+		kind, namespace, name string
+		skipped, failed       bool
+	}
+	var got []outcome
+	for _, r := range results {
+		got = append(got, outcome{r.Kind, r.Namespace, r.Name, r.Skipped, r.Err != nil})
+	}
+	want := []outcome{
+		{kind: "CertificateExport", namespace: "backend", name: "app"},
+		{kind: "CertificateExport", namespace: "backend", name: "paused", skipped: true},
+		{kind: "CertificateImport", namespace: "frontend", name: "app"},
+		{kind: "CertificateImport", namespace: "frontend", name: "broken", failed: true},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("got results %+v, want %+v", got, want)
+	}
+	if getSecret(t, c, "frontend", "app-tls") == nil {
+		t.Error("the import that synced did not write its target")
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// isOverdue reports whether imp missed a whole scheduled run: its last
+// successful sync, or its creation if it never synced, is followed by a run
+// that was due and by the next one, and neither synced it. The jitter delay
+// of the import is allowed for.
+func (s *SyncController) isOverdue(obj *unstructured.Unstructured, now time.Time) (bool, error) {
+	imp, err := toImport(obj)
+	if err != nil {
+		return false, err
+	}
+	spec, err := scheduleFor(imp.Spec.Schedule, imp.Spec.Timezone)
+	if err != nil {
+		return false, err
+	}
+	sched, err := parseSchedule(spec)
+	if err != nil {
+		return false, err
+	}
+	jitter, err := s.importJitter(imp)
+	if err != nil {
+		return false, err
+	}
+	last := imp.CreationTimestamp.Time
+	if imp.Status.LastSyncTime != nil {
+		last = imp.Status.LastSyncTime.Time
+	}
+	due := sched.Next(sched.Next(last)).Add(jitterDelay(string(imp.UID), jitter))
+	return now.After(due), nil
+}
+
+// updateOverdue sets certtrust_import_overdue for every scheduled import.
+// Imports that are no longer scheduled, e.g. suspended or deleted ones, lose
+// their series.
+func (s *SyncController) updateOverdue(imports []unstructured.Unstructured, now time.Time) {
+	importOverdue.Reset()
+	for i := range imports {
+		overdue, err := s.isOverdue(&imports[i], now)
+		if err != nil {
+			// an invalid schedule is reported when scheduling the import
+			continue
+		}
+		value := 0.0
+		if overdue {
+			value = 1
+		}
+		importOverdue.WithLabelValues(imports[i].GetNamespace(), imports[i].GetName()).Set(value)
+	}
+}
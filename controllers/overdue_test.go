@@ -0,0 +1,98 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// newHourlyImport returns an import synced every hour on the hour (UTC),
+// created at created and last synced at lastSync, if set.
+func newHourlyImport(name string, created time.Time, lastSync string, jitter string) *unstructured.Unstructured {
+	spec := map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls", "schedule": "0 * * * *", "timezone": "UTC"}
+	if jitter != "" {
+		spec["jitter"] = jitter
+	}
+	imp := newImport("frontend", name, spec)
+	imp.SetUID("uid-" + types.UID(name))
+	imp.SetCreationTimestamp(metav1.NewTime(created))
+	if lastSync != "" {
+		setString(imp.Object, "status.lastSyncTime", lastSync)
+	}
+	return imp
+}
+
+func TestIsOverdue(t *testing.T) {
+	created := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	at := func(hour, min int) time.Time { return time.Date(2025, 6, 1, hour, min, 0, 0, time.UTC) }
+	tests := []struct {
+		name     string
+		lastSync string
+		jitter   string
+		now      time.Time
+		want     bool
+	}{
+		{name: "before the next run", lastSync: "2025-06-01T10:00:00Z", now: at(10, 30)},
+		{name: "one run missed, the next not yet due", lastSync: "2025-06-01T10:00:00Z", now: at(11, 59)},
+		{name: "a whole run missed", lastSync: "2025-06-01T10:00:00Z", now: at(12, 1), want: true},
+		{name: "never synced", now: at(10, 1), want: true},
+		{name: "never synced, just created", now: at(9, 59)},
+		{name: "within the jitter", lastSync: "2025-06-01T10:00:00Z", jitter: "1h", now: at(12, 1)},
+		{name: "past the jitter", lastSync: "2025-06-01T10:00:00Z", jitter: "1h", now: at(13, 1), want: true},
+	}
+	s, _ := newTestController(t, Options{})
+	for _, tt := range tests {
+		got, err := s.isOverdue(newHourlyImport("app", created, tt.lastSync, tt.jitter), tt.now)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		if got != tt.want {
+			t.Errorf("%s: got overdue %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateOverdue(t *testing.T) {
+	created := time.Date(2025, 6, 1, 8, 30, 0, 0, time.UTC)
+	s, _ := newTestController(t, Options{})
+	imports := []unstructured.Unstructured{
+		*newHourlyImport("stuck", created, "2025-06-01T10:00:00Z", ""),
+		*newHourlyImport("fresh", created, "2025-06-01T12:00:00Z", ""),
+	}
+	now := time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+	s.updateOverdue(imports, now)
+	if got := testutil.ToFloat64(importOverdue.WithLabelValues("frontend", "stuck")); got != 1 {
+		t.Errorf("got overdue %v for the stuck import, want 1", got)
+	}
+	if got := testutil.ToFloat64(importOverdue.WithLabelValues("frontend", "fresh")); got != 0 {
+		t.Errorf("got overdue %v for the fresh import, want 0", got)
+	}
+
+	// the stuck import syncs again, and the fresh one is no longer scheduled
+	setString(imports[0].Object, "status.lastSyncTime", "2025-06-01T12:29:00Z")
+	s.updateOverdue(imports[:1], now)
+	if got := testutil.ToFloat64(importOverdue.WithLabelValues("frontend", "stuck")); got != 0 {
+		t.Errorf("got overdue %v after the import synced, want 0", got)
+	}
+	if n := testutil.CollectAndCount(importOverdue); n != 1 {
+		t.Errorf("got %d overdue series, want the one of the scheduled import", n)
+	}
+}
@@ -0,0 +1,47 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+// defaultPKCS12Key is the key of the keystore in the target secret when
+// spec.pkcs12.key is unset.
+const defaultPKCS12Key = "keystore.p12"
+
+// encodePKCS12 encodes the key pair in src, with its chain and the
+// certificates of ca.crt, as a PKCS#12 keystore. Without a key pair it
+// encodes a trust store of the ca.crt certificates.
+func encodePKCS12(_ map[string]interface{}, src map[string][]byte, password string) ([]byte, error) {
+	caCerts, err := parseCertificates(src["ca.crt"])
+	if err != nil {
+		return nil, err
+	}
+	if secretTypeFor(src) != corev1.SecretTypeTLS {
+		if len(caCerts) == 0 {
+			return nil, fmt.Errorf("no key pair or CA certificates to store")
+		}
+		return pkcs12.Modern.EncodeTrustStore(caCerts, password)
+	}
+	key, chain, err := keyPairChain(src)
+	if err != nil {
+		return nil, err
+	}
+	return pkcs12.Modern.Encode(key, chain[0], append(chain[1:], caCerts...), password)
+}
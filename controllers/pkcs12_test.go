@@ -0,0 +1,96 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/pem"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+func TestSyncImportPKCS12(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	ca, _ := newKeyPair(t, "ca")
+	leafDER, _ := pem.Decode(crt)
+	caDER, _ := pem.Decode(ca)
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key, "ca.crt": ca}),
+		newExport("backend", "app", "app-tls"),
+		newSecret("frontend", "keystore-pass", corev1.SecretTypeOpaque, map[string][]byte{"password": []byte("changeit")}),
+		newImport("frontend", "app", map[string]interface{}{
+			"fromExport":   "backend/app",
+			"targetSecret": "app-tls",
+			"pkcs12":       map[string]interface{}{"passwordSecretRef": map[string]interface{}{"name": "keystore-pass"}},
+		}),
+	)
+	ctx := context.Background()
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	p12 := getSecret(t, c, "frontend", "app-tls").Data[defaultPKCS12Key]
+	if len(p12) == 0 {
+		t.Fatalf("target has no %s", defaultPKCS12Key)
+	}
+
+	if _, _, _, err := pkcs12.DecodeChain(p12, "wrong"); err == nil {
+		t.Error("keystore decodes with the wrong password")
+	}
+	privateKey, leaf, caCerts, err := pkcs12.DecodeChain(p12, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(leaf.Raw, leafDER.Bytes) {
+		t.Error("keystore certificate is not the source tls.crt")
+	}
+	if ecKey, ok := privateKey.(*ecdsa.PrivateKey); !ok || !ecKey.PublicKey.Equal(leaf.PublicKey) {
+		t.Errorf("keystore key %T does not match its certificate", privateKey)
+	}
+	if len(caCerts) != 1 || !bytes.Equal(caCerts[0].Raw, caDER.Bytes) {
+		t.Errorf("got %d CA certificates, want the source ca.crt", len(caCerts))
+	}
+
+	// encoding is randomized, so an unchanged source must keep the keystore
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if again := getSecret(t, c, "frontend", "app-tls").Data[defaultPKCS12Key]; !bytes.Equal(again, p12) {
+		t.Error("keystore was rebuilt from an unchanged source")
+	}
+}
+
+func TestEncodePKCS12TrustStore(t *testing.T) {
+	ca, _ := newKeyPair(t, "ca")
+	caDER, _ := pem.Decode(ca)
+	p12, err := encodePKCS12(nil, map[string][]byte{"ca.crt": ca}, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	certs, err := pkcs12.DecodeTrustStore(p12, "changeit")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(certs) != 1 || !bytes.Equal(certs[0].Raw, caDER.Bytes) {
+		t.Errorf("got %d trusted certificates, want the source ca.crt", len(certs))
+	}
+
+	if _, err := encodePKCS12(nil, map[string][]byte{"other": []byte("x")}, "changeit"); err == nil {
+		t.Error("encoded a keystore without a key pair or CA certificates")
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"crypto/tls"
+	"fmt"
+
+	corev1 "k8s.io/api/core/v1"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
+)
+
+// planImport computes the data and type of the target secret of the import
+// namespace/name from its source secret src, whose data already includes the
+// CAs merged from spec.fromExports. It has no side effects, so syncImport
+// only has to apply the result. Keystores, which need their password
+// secrets, are added by the caller from selected, the copied source keys
+// before keyMap renames them. Unless spec.verifyKeyPair is false, a copied
+// tls.crt/tls.key pair that does not match fails with ErrInvalidCertificate.
+func planImport(src *corev1.Secret, spec *certtrustv1.CertificateImportSpec, namespace, name string) (selected, desired map[string][]byte, secretType corev1.SecretType, err error) {
+	selected, err = selectSourceData(src.Data, spec)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("import %s/%s: %w", namespace, name, err)
+	}
+	if (spec.VerifyKeyPair == nil || *spec.VerifyKeyPair) && secretTypeFor(selected) == corev1.SecretTypeTLS {
+		if _, err := tls.X509KeyPair(selected[corev1.TLSCertKey], selected[corev1.TLSPrivateKeyKey]); err != nil {
+			return nil, nil, "", fmt.Errorf("%w: source secret %s/%s: %v", ErrInvalidCertificate, src.Namespace, src.Name, err)
+		}
+	}
+	keyMap, err := renderKeyMap(spec.KeyMap, namespace, name)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("import %s/%s: %w", namespace, name, err)
+	}
+	desired, err = mapKeys(selected, keyMap)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("import %s/%s: %w", namespace, name, err)
+	}
+	// split the source CA bundle into one key per certificate
+	if spec.SplitCABundle {
+		split, err := splitCABundle(src.Data["ca.crt"], spec.SplitCABundleCAOnly)
+		if err != nil {
+			return nil, nil, "", fmt.Errorf("import %s/%s: %w", namespace, name, err)
+		}
+		for k, v := range split {
+			if _, ok := desired[k]; ok {
+				return nil, nil, "", fmt.Errorf("import %s/%s: split CA bundle key %q collides with a copied key", namespace, name, k)
+			}
+			desired[k] = v
+		}
+	}
+	secretType, err = targetSecretType(spec.TargetType, desired)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("import %s/%s: %w", namespace, name, err)
+	}
+	return selected, desired, secretType, nil
+}
+
+// selectSourceData returns the keys of srcData an import copies, under their
+// source names: spec.includeKeys/excludeKeys applied and, with
+// spec.normalizePEM, PEM values normalized.
+func selectSourceData(srcData map[string][]byte, spec *certtrustv1.CertificateImportSpec) (map[string][]byte, error) {
+	selected, err := selectKeys(srcData, spec.IncludeKeys, spec.ExcludeKeys)
+	if err != nil {
+		return nil, err
+	}
+	if spec.NormalizePEM {
+		selected = normalizePEMData(selected)
+	}
+	return selected, nil
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"errors"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
+)
+
+func TestPlanImport(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	otherCrt, _ := newKeyPair(t, "other")
+	ca, _ := newKeyPair(t, "ca")
+	src := &corev1.Secret{Type: corev1.SecretTypeTLS, Data: map[string][]byte{
+		corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key, "ca.crt": ca,
+	}}
+	src.Namespace, src.Name = "backend", "app-tls"
+	mismatched := src.DeepCopy()
+	mismatched.Data[corev1.TLSCertKey] = otherCrt
+	no := false
+
+	tests := []struct {
+		name         string
+		src          *corev1.Secret
+		spec         certtrustv1.CertificateImportSpec
+		wantSelected []string
+		wantDesired  []string
+		wantType     corev1.SecretType
+		wantErr      bool
+		// wantErrIs, if set, is the error wrapped by the failure
+		wantErrIs error
+	}{
+		{
+			name:         "copies every key",
+			wantSelected: []string{"ca.crt", "tls.crt", "tls.key"},
+			wantDesired:  []string{"ca.crt", "tls.crt", "tls.key"},
+			wantType:     corev1.SecretTypeTLS,
+		},
+		{
+			name:         "excludes the key",
+			spec:         certtrustv1.CertificateImportSpec{ExcludeKeys: []string{"tls.key"}},
+			wantSelected: []string{"ca.crt", "tls.crt"},
+			wantDesired:  []string{"ca.crt", "tls.crt"},
+			wantType:     corev1.SecretTypeOpaque,
+		},
+		{
+			name:         "renames after selecting",
+			spec:         certtrustv1.CertificateImportSpec{KeyMap: map[string]string{"tls.crt": "cert.pem", "tls.key": "key.pem"}},
+			wantSelected: []string{"ca.crt", "tls.crt", "tls.key"},
+			wantDesired:  []string{"ca.crt", "cert.pem", "key.pem"},
+			wantType:     corev1.SecretTypeOpaque,
+		},
+		{
+			name:         "splits the CA bundle into the desired keys only",
+			spec:         certtrustv1.CertificateImportSpec{IncludeKeys: []string{"ca.crt"}, SplitCABundle: true},
+			wantSelected: []string{"ca.crt"},
+			wantDesired:  []string{"ca-0.crt", "ca.crt"},
+			wantType:     corev1.SecretTypeOpaque,
+		},
+		{
+			name:    "rejects a selection without keys",
+			spec:    certtrustv1.CertificateImportSpec{IncludeKeys: []string{"missing"}},
+			wantErr: true,
+		},
+		{
+			name:      "rejects a mismatched pair",
+			src:       mismatched,
+			wantErr:   true,
+			wantErrIs: ErrInvalidCertificate,
+		},
+		{
+			name:         "copies a mismatched pair without verifyKeyPair",
+			src:          mismatched,
+			spec:         certtrustv1.CertificateImportSpec{VerifyKeyPair: &no},
+			wantSelected: []string{"ca.crt", "tls.crt", "tls.key"},
+			wantDesired:  []string{"ca.crt", "tls.crt", "tls.key"},
+			wantType:     corev1.SecretTypeTLS,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := tt.src
+			if in == nil {
+				in = src
+			}
+			before := joinKeys(in.Data)
+			selected, desired, secretType, err := planImport(in, &tt.spec, "frontend", "app")
+			if joinKeys(in.Data) != before {
+				t.Errorf("planImport changed the source keys to %s", joinKeys(in.Data))
+			}
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("planned despite the error")
+				}
+				if tt.wantErrIs != nil && !errors.Is(err, tt.wantErrIs) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErrIs)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := joinKeys(selected); got != joinKeys(keySet(tt.wantSelected)) {
+				t.Errorf("got selected keys %s, want %v", got, tt.wantSelected)
+			}
+			if got := joinKeys(desired); got != joinKeys(keySet(tt.wantDesired)) {
+				t.Errorf("got desired keys %s, want %v", got, tt.wantDesired)
+			}
+			if secretType != tt.wantType {
+				t.Errorf("got type %s, want %s", secretType, tt.wantType)
+			}
+		})
+	}
+}
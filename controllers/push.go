@@ -0,0 +1,270 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// pushedByAnnotation marks a secret as written by the CertificateExport named
+// in its value (namespace/name) through the push model.
+const pushedByAnnotation = annotationPrefix + "pushed-by"
+
+// syncExportPush copies the source secret of an export into spec.targetSecret
+// in every namespace listed in spec.targetNamespaces or matching
+// spec.targetNamespaceSelector. Namespaces are resolved on every run so new
+// matching namespaces are picked up on the next sync.
+func (s *SyncController) syncExportPush(ctx context.Context, namespace, name string) (err error) {
+	ctx, span := startSpan(ctx, "syncExportPush", attribute.String("export.namespace", namespace), attribute.String("export.name", name))
+	defer func() { endSpan(span, err) }()
+	logger := log.FromContext(ctx).WithValues("export", fmt.Sprintf("%s/%s", namespace, name))
+	release, err := s.acquireSync(ctx, scheduleKey("CertificateExport", namespace, name))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	exp := &unstructured.Unstructured{}
+	exp.SetGroupVersionKind(schemaGVK("CertificateExport"))
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, exp); err != nil {
+		logger.Error(err, "failed to get export")
+		return err
+	}
+	if isSuspended(exp) {
+		logger.Info("export is suspended, skipping push")
+		return nil
+	}
+	remote := isRemoteExport(exp)
+	if remote {
+		if wait := s.remoteBackoffRemaining(namespace + "/" + name); wait > 0 {
+			logger.Info("remote cluster was unreachable, skipping push", "retryIn", wait)
+			return nil
+		}
+	}
+	defer func() { s.recordSyncResult(exp, err, "pushed secret to target namespaces") }()
+	secretRef := getString(exp.Object, "spec.secretRef")
+	targetSecret := getString(exp.Object, "spec.targetSecret")
+
+	var src corev1.Secret
+	if err := s.getSourceSecret(ctx, types.NamespacedName{Namespace: namespace, Name: secretRef}, &src); err != nil {
+		logger.Error(err, "failed to get source secret", "secretRef", secretRef)
+		return err
+	}
+	if err := checkSourceType(exp, &src); err != nil {
+		return err
+	}
+
+	// Remote exports push into the cluster of their kubeconfig secret
+	target := s.Client
+	if remote {
+		if target, err = s.remoteClient(ctx, exp); err != nil {
+			logger.Error(err, "failed to build remote client")
+			return err
+		}
+	}
+
+	namespaces, err := s.pushNamespaces(ctx, target, exp)
+	if err != nil {
+		logger.Error(err, "failed to resolve target namespaces")
+		if remote && isUnreachable(err) {
+			s.remoteUnreachable(ctx, exp, err)
+		}
+		return err
+	}
+
+	owner := fmt.Sprintf("%s/%s", namespace, name)
+	var errs []error
+	keep := make(map[string]bool, len(namespaces))
+	for _, ns := range namespaces {
+		keep[ns] = true
+		if !remote && ns == namespace && targetSecret == secretRef {
+			// never overwrite the source with itself
+			continue
+		}
+		if !remote && !s.namespaceAllowed(ns) {
+			logger.Info("skipping push into namespace outside the controller's scope", "namespace", ns)
+			continue
+		}
+		if err := s.pushSecret(ctx, target, owner, ns, targetSecret, &src); err != nil {
+			logger.Error(err, "failed to push secret", "namespace", ns, "targetSecret", targetSecret)
+			errs = append(errs, err)
+			continue
+		}
+	}
+	// namespaces that no longer match, or a renamed targetSecret, must not
+	// keep serving the source
+	if err := s.prunePushed(ctx, target, !remote, owner, targetSecret, keep); err != nil {
+		logger.Error(err, "failed to delete secrets pushed to namespaces no longer targeted")
+		errs = append(errs, err)
+	}
+	logger.V(1).Info("export push completed", "namespaces", len(namespaces), "failed", len(errs))
+	if len(errs) > 0 {
+		err := errors.Join(errs...)
+		if remote && isUnreachable(err) {
+			s.remoteUnreachable(ctx, exp, err)
+		}
+		return err
+	}
+	if remote {
+		s.remoteReachable(exp)
+	}
+
+	// Record the sync in the status of the export (best-effort)
+	_ = s.updateStatus(ctx, exp, func(exp *unstructured.Unstructured) bool {
+		markSynced(exp)
+		setCertificateStatus(exp, src.Data)
+		removeCondition(exp, conditionRemoteUnreachable)
+		if next, err := s.nextSyncTime(exp, time.Now()); err == nil {
+			setNextSyncTime(exp, next)
+		}
+		return true
+	})
+	return nil
+}
+
+// pushNamespaces returns the sorted, deduplicated set of namespaces an export
+// pushes to.
+func (s *SyncController) pushNamespaces(ctx context.Context, r client.Reader, exp *unstructured.Unstructured) ([]string, error) {
+	set := map[string]bool{}
+	for _, ns := range getStringSlice(exp.Object, "spec.targetNamespaces") {
+		set[ns] = true
+	}
+	// a namespace-scoped controller may not list namespaces, and could only
+	// push into its own one anyway
+	if raw, ok, _ := unstructured.NestedMap(exp.Object, "spec", "targetNamespaceSelector"); ok && s.opts.Namespace == "" {
+		var ls metav1.LabelSelector
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(raw, &ls); err != nil {
+			return nil, fmt.Errorf("invalid targetNamespaceSelector: %w", err)
+		}
+		sel, err := metav1.LabelSelectorAsSelector(&ls)
+		if err != nil {
+			return nil, fmt.Errorf("invalid targetNamespaceSelector: %w", err)
+		}
+		var nsList corev1.NamespaceList
+		if err := r.List(ctx, &nsList, client.MatchingLabelsSelector{Selector: sel}); err != nil {
+			return nil, err
+		}
+		for i := range nsList.Items {
+			if nsList.Items[i].DeletionTimestamp.IsZero() {
+				set[nsList.Items[i].Name] = true
+			}
+		}
+	}
+	out := make([]string, 0, len(set))
+	for ns := range set {
+		out = append(out, ns)
+	}
+	sort.Strings(out)
+	return out, nil
+}
+
+// pushSecret creates or updates name in namespace with the data of src using
+// c. An existing secret is only overwritten when it was pushed by the same
+// export, and not at all when it already holds the same type and data.
+func (s *SyncController) pushSecret(ctx context.Context, c client.Client, owner, namespace, name string, src *corev1.Secret) error {
+	var tgt corev1.Secret
+	err := c.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, &tgt)
+	if apierrors.IsNotFound(err) {
+		tgt = corev1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace:   namespace,
+				Name:        name,
+				Annotations: map[string]string{pushedByAnnotation: owner},
+			},
+			Type: src.Type,
+			Data: src.Data,
+		}
+		return c.Create(ctx, &tgt)
+	}
+	if err != nil {
+		return err
+	}
+	if pushedBy := tgt.Annotations[pushedByAnnotation]; pushedBy != owner {
+		return fmt.Errorf("%w: secret %s/%s is not managed by export %s (pushed-by: %q)", ErrTargetNotManaged, namespace, name, owner, pushedBy)
+	}
+	if tgt.Type == src.Type && equality.Semantic.DeepEqual(tgt.Data, src.Data) {
+		// already identical, avoid a no-op write
+		return nil
+	}
+	tgt.Type = src.Type
+	tgt.Data = src.Data
+	return c.Update(ctx, &tgt)
+}
+
+// prunePushed deletes the secrets pushed by owner through c, except name in
+// the namespaces in keep. In the local cluster, secrets in namespaces outside
+// the controller's scope are left alone.
+func (s *SyncController) prunePushed(ctx context.Context, c client.Client, local bool, owner, name string, keep map[string]bool) error {
+	logger := log.FromContext(ctx).WithValues("export", owner)
+	var opts []client.ListOption
+	if local {
+		opts = append(opts, client.InNamespace(s.opts.Namespace))
+	}
+	var secrets corev1.SecretList
+	if err := c.List(ctx, &secrets, opts...); err != nil {
+		return err
+	}
+	var errs []error
+	for i := range secrets.Items {
+		secret := &secrets.Items[i]
+		if secret.Annotations[pushedByAnnotation] != owner || (secret.Name == name && keep[secret.Namespace]) {
+			continue
+		}
+		if local && !s.namespaceAllowed(secret.Namespace) {
+			continue
+		}
+		uid, rv := secret.UID, secret.ResourceVersion
+		if err := c.Delete(ctx, secret, client.Preconditions{UID: &uid, ResourceVersion: &rv}); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, err)
+			continue
+		}
+		logger.Info("deleted pushed secret no longer targeted", "secret", client.ObjectKeyFromObject(secret).String())
+	}
+	return errors.Join(errs...)
+}
+
+// cleanupExport deletes every secret exp pushed. A remote export whose
+// kubeconfig secret is gone can't reach its cluster any more; its secrets
+// there are left behind.
+func (s *SyncController) cleanupExport(ctx context.Context, exp *unstructured.Unstructured) error {
+	target := s.Client
+	if isRemoteExport(exp) {
+		c, err := s.remoteClient(ctx, exp)
+		if apierrors.IsNotFound(err) {
+			log.FromContext(ctx).Info("kubeconfig secret of remote export is gone, leaving its pushed secrets in place", "export", fmt.Sprintf("%s/%s", exp.GetNamespace(), exp.GetName()))
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		target = c
+	}
+	return s.prunePushed(ctx, target, !isRemoteExport(exp), fmt.Sprintf("%s/%s", exp.GetNamespace(), exp.GetName()), "", nil)
+}
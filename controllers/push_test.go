@@ -0,0 +1,176 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// newPushExport returns an export pushing secretRef as targetSecret into
+// namespaces.
+func newPushExport(namespace, name, secretRef, targetSecret string, namespaces ...interface{}) *unstructured.Unstructured {
+	exp := newExport(namespace, name, secretRef)
+	setString(exp.Object, "spec.targetSecret", targetSecret)
+	_ = unstructured.SetNestedSlice(exp.Object, namespaces, "spec", "targetNamespaces")
+	return exp
+}
+
+// newPushedSecret returns a secret annotated as pushed by owner.
+func newPushedSecret(namespace, name, owner string) *corev1.Secret {
+	sec := newSecret(namespace, name, corev1.SecretTypeOpaque, map[string][]byte{"ca.crt": []byte("old")})
+	sec.Annotations = map[string]string{pushedByAnnotation: owner}
+	return sec
+}
+
+func TestSyncExportPushPrunesUntargetedSecrets(t *testing.T) {
+	crt, key := newKeyPair(t, "ca")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newPushExport("backend", "ca", "ca-tls", "ca-tls", "api", "web"),
+		// left behind by an earlier targetSecret
+		newPushedSecret("api", "old-ca", "backend/ca"),
+		newPushedSecret("web", "other-ca", "backend/other"),
+	)
+	ctx := context.Background()
+	if err := s.syncExportPush(ctx, "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	for _, ns := range []string{"api", "web"} {
+		if sec := getSecret(t, c, ns, "ca-tls"); sec == nil || sec.Annotations[pushedByAnnotation] != "backend/ca" {
+			t.Errorf("got secret %v in %s, want one pushed by backend/ca", sec, ns)
+		}
+	}
+	if getSecret(t, c, "api", "old-ca") != nil {
+		t.Error("the secret of the former targetSecret was kept")
+	}
+
+	exp := &unstructured.Unstructured{}
+	exp.SetGroupVersionKind(schemaGVK("CertificateExport"))
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "backend", Name: "ca"}, exp); err != nil {
+		t.Fatal(err)
+	}
+	_ = unstructured.SetNestedSlice(exp.Object, []interface{}{"api"}, "spec", "targetNamespaces")
+	if err := c.Update(ctx, exp); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncExportPush(ctx, "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	if getSecret(t, c, "api", "ca-tls") == nil {
+		t.Error("the secret of a still targeted namespace was deleted")
+	}
+	if getSecret(t, c, "web", "ca-tls") != nil {
+		t.Error("the secret of a namespace no longer targeted was kept")
+	}
+	if getSecret(t, c, "web", "other-ca") == nil {
+		t.Error("the secret pushed by another export was deleted")
+	}
+}
+
+func TestReconcileExportFinalizers(t *testing.T) {
+	crt, key := newKeyPair(t, "ca")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newPushExport("backend", "ca", "ca-tls", "ca-tls", "api"),
+		newExport("backend", "plain", "ca-tls"),
+	)
+	ctx := context.Background()
+	getExport := func(name string) *unstructured.Unstructured {
+		exp := &unstructured.Unstructured{}
+		exp.SetGroupVersionKind(schemaGVK("CertificateExport"))
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "backend", Name: name}, exp); err != nil {
+			if apierrors.IsNotFound(err) {
+				return nil
+			}
+			t.Fatal(err)
+		}
+		return exp
+	}
+
+	live := s.reconcileExportFinalizers(ctx, []unstructured.Unstructured{*getExport("ca"), *getExport("plain")})
+	if len(live) != 2 {
+		t.Fatalf("got %d live exports, want 2", len(live))
+	}
+	if !controllerutil.ContainsFinalizer(getExport("ca"), cleanupFinalizer) {
+		t.Error("no finalizer was added to a push export")
+	}
+	if controllerutil.ContainsFinalizer(getExport("plain"), cleanupFinalizer) {
+		t.Error("a finalizer was added to an export that does not push")
+	}
+	if err := s.syncExportPush(ctx, "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := c.Delete(ctx, getExport("ca")); err != nil {
+		t.Fatal(err)
+	}
+	if live := s.reconcileExportFinalizers(ctx, []unstructured.Unstructured{*getExport("ca")}); len(live) != 0 {
+		t.Errorf("got %d live exports, want the deleted one dropped", len(live))
+	}
+	if getSecret(t, c, "api", "ca-tls") != nil {
+		t.Error("the pushed secret outlived its export")
+	}
+	if getExport("ca") != nil {
+		t.Error("the export was not released by its finalizer")
+	}
+}
+
+func TestReconcileExportFinalizersStoppedPushing(t *testing.T) {
+	exp := newExport("backend", "ca", "ca-tls")
+	exp.SetFinalizers([]string{cleanupFinalizer})
+	s, c := newTestController(t, Options{}, exp, newPushedSecret("api", "ca-tls", "backend/ca"))
+	ctx := context.Background()
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "backend", Name: "ca"}, exp); err != nil {
+		t.Fatal(err)
+	}
+	if live := s.reconcileExportFinalizers(ctx, []unstructured.Unstructured{*exp}); len(live) != 1 {
+		t.Fatalf("got %d live exports, want 1", len(live))
+	}
+	if getSecret(t, c, "api", "ca-tls") != nil {
+		t.Error("the secret pushed before the export stopped pushing was kept")
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "backend", Name: "ca"}, exp); err != nil {
+		t.Fatal(err)
+	}
+	if controllerutil.ContainsFinalizer(exp, cleanupFinalizer) {
+		t.Error("the finalizer of an export that stopped pushing was kept")
+	}
+}
+
+func TestSyncExportPushSkipsUnchangedWrite(t *testing.T) {
+	crt, key := newKeyPair(t, "ca")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newPushExport("backend", "ca", "ca-tls", "ca-tls", "api"),
+	)
+	ctx := context.Background()
+	if err := s.syncExportPush(ctx, "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	first := getSecret(t, c, "api", "ca-tls")
+	if err := s.syncExportPush(ctx, "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	if second := getSecret(t, c, "api", "ca-tls"); second.ResourceVersion != first.ResourceVersion {
+		t.Errorf("unchanged source updated the pushed secret: resourceVersion %s -> %s", first.ResourceVersion, second.ResourceVersion)
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"golang.org/x/time/rate"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// rateLimitedClient delays writes, including subresource writes, until the
+// token bucket allows them. Reads are not limited.
+type rateLimitedClient struct {
+	client.Client
+	limiter *rate.Limiter
+}
+
+// newRateLimitedClient limits the writes of c to qps per second with bursts
+// of up to burst writes. A non-positive qps leaves c unlimited.
+func newRateLimitedClient(c client.Client, qps float64, burst int) client.Client {
+	if qps <= 0 {
+		return c
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &rateLimitedClient{Client: c, limiter: rate.NewLimiter(rate.Limit(qps), burst)}
+}
+
+func (c *rateLimitedClient) Create(ctx context.Context, obj client.Object, opts ...client.CreateOption) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Create(ctx, obj, opts...)
+}
+
+func (c *rateLimitedClient) Update(ctx context.Context, obj client.Object, opts ...client.UpdateOption) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Update(ctx, obj, opts...)
+}
+
+func (c *rateLimitedClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.PatchOption) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Patch(ctx, obj, patch, opts...)
+}
+
+func (c *rateLimitedClient) Delete(ctx context.Context, obj client.Object, opts ...client.DeleteOption) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.Delete(ctx, obj, opts...)
+}
+
+func (c *rateLimitedClient) DeleteAllOf(ctx context.Context, obj client.Object, opts ...client.DeleteAllOfOption) error {
+	if err := c.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return c.Client.DeleteAllOf(ctx, obj, opts...)
+}
+
+func (c *rateLimitedClient) Status() client.SubResourceWriter {
+	return &rateLimitedSubResourceClient{SubResourceClient: c.Client.SubResource("status"), limiter: c.limiter}
+}
+
+func (c *rateLimitedClient) SubResource(subResource string) client.SubResourceClient {
+	return &rateLimitedSubResourceClient{SubResourceClient: c.Client.SubResource(subResource), limiter: c.limiter}
+}
+
+// rateLimitedSubResourceClient shares the token bucket of its
+// rateLimitedClient for subresource writes, e.g. status updates.
+type rateLimitedSubResourceClient struct {
+	client.SubResourceClient
+	limiter *rate.Limiter
+}
+
+func (s *rateLimitedSubResourceClient) Create(ctx context.Context, obj client.Object, subResource client.Object, opts ...client.SubResourceCreateOption) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Create(ctx, obj, subResource, opts...)
+}
+
+func (s *rateLimitedSubResourceClient) Update(ctx context.Context, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Update(ctx, obj, opts...)
+}
+
+func (s *rateLimitedSubResourceClient) Patch(ctx context.Context, obj client.Object, patch client.Patch, opts ...client.SubResourcePatchOption) error {
+	if err := s.limiter.Wait(ctx); err != nil {
+		return err
+	}
+	return s.SubResourceClient.Patch(ctx, obj, patch, opts...)
+}
@@ -0,0 +1,81 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestNewRateLimitedClientUnlimited(t *testing.T) {
+	_, c := newTestController(t, Options{})
+	if _, ok := newRateLimitedClient(c, 0, 10).(*rateLimitedClient); ok {
+		t.Error("a zero qps limited the client")
+	}
+}
+
+func TestSyncImportWriteRateLimit(t *testing.T) {
+	const qps = 20
+	crt, key := newKeyPair(t, "app")
+	var mu sync.Mutex
+	var writes []time.Time
+	record := func() {
+		mu.Lock()
+		defer mu.Unlock()
+		writes = append(writes, time.Now())
+	}
+	s, c := newInterceptedTestController(t, Options{}, interceptor.Funcs{
+		Create: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.CreateOption) error {
+			record()
+			return c.Create(ctx, obj, opts...)
+		},
+		Update: func(ctx context.Context, c client.WithWatch, obj client.Object, opts ...client.UpdateOption) error {
+			record()
+			return c.Update(ctx, obj, opts...)
+		},
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			record()
+			return c.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+	},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("web", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("api", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	s.Client = newRateLimitedClient(c, qps, 1)
+	for _, ns := range []string{"frontend", "web", "api"} {
+		if err := s.syncImport(context.Background(), ns, "app"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(writes) < 6 {
+		t.Fatalf("got %d writes, want a target and a status write per import", len(writes))
+	}
+	// a burst of one spaces every write by 1/qps, less timer slack
+	minGap := time.Second / qps * 8 / 10
+	for i := 1; i < len(writes); i++ {
+		if gap := writes[i].Sub(writes[i-1]); gap < minGap {
+			t.Errorf("write %d followed the previous one after %v, want at least %v", i, gap, minGap)
+		}
+	}
+}
@@ -15,13 +15,176 @@
 package controllers
 
 import (
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/conversion"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
+	certtrustv2 "github.com/nazman/cert-trust/api/v2"
 )
 
-func RegisterWithManager(mgr ctrl.Manager, immediateOnStart bool) error {
-	c := NewSyncController(mgr.GetClient(), mgr.GetScheme(), immediateOnStart)
+func RegisterWithManager(mgr ctrl.Manager, opts Options) error {
+	if opts.Namespace != "" {
+		opts.WatchNamespaces = []string{opts.Namespace}
+		opts.ClusterTrustBundles = false
+	}
+	if opts.ClusterTrustBundles && !clusterTrustBundlesServed(mgr.GetRESTMapper()) {
+		ctrl.Log.Info("ClusterTrustBundle API not served by the cluster, not publishing cluster trust bundles")
+		opts.ClusterTrustBundles = false
+	}
+	cl := newRateLimitedClient(mgr.GetClient(), opts.WriteQPS, opts.WriteBurst)
+	recorder := mgr.GetEventRecorderFor("cert-trust")
+	if opts.DryRun {
+		// Writes, including events, are only logged
+		cl = newDryRunClient(cl)
+		recorder = nil
+	}
+	c := NewSyncController(cl, mgr.GetCache(), mgr.GetScheme(), recorder, opts)
+	c.apiReader = mgr.GetAPIReader()
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("drift").
+		Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(managedSecretImport), builder.WithPredicates(driftPredicate())).
+		Complete(&driftReconciler{s: c}); err != nil {
+		return err
+	}
+	if opts.SyncOnSecretChange {
+		if err := ctrl.NewControllerManagedBy(mgr).
+			Named("source-rotation").
+			Watches(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(c.sourceSecretImports), builder.WithPredicates(rotationPredicate())).
+			Complete(&rotationReconciler{s: c}); err != nil {
+			return err
+		}
+	}
+	syncNowImport := &unstructured.Unstructured{}
+	syncNowImport.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := ctrl.NewControllerManagedBy(mgr).
+		Named("sync-now").
+		For(syncNowImport, builder.WithPredicates(syncNowPredicate())).
+		Complete(&syncNowReconciler{s: c}); err != nil {
+		return err
+	}
+	if opts.DebugBindAddress != "" {
+		if err := mgr.Add(&debugServer{s: c, addr: opts.DebugBindAddress}); err != nil {
+			return err
+		}
+	}
+	if err := mgr.AddReadyzCheck("schedules", c.schedulesReady(mgr.Elected())); err != nil {
+		return err
+	}
+	if err := mgr.AddHealthzCheck("reschedule-loop", c.rescheduleAlive()); err != nil {
+		return err
+	}
 	return mgr.Add(c)
 }
 
-func AddToScheme(s *runtime.Scheme) error { return nil }
+// SyncImport runs a single sync of the CertificateImport namespace/name with
+// c, outside of any manager. Events are not recorded.
+func SyncImport(ctx context.Context, c client.Client, scheme *runtime.Scheme, opts Options, namespace, name string) error {
+	if opts.DryRun {
+		c = newDryRunClient(c)
+	}
+	return NewSyncController(c, nil, scheme, nil, opts).syncImport(ctx, namespace, name)
+}
+
+// RegisterWebhooksWithManager serves the validating and defaulting admission
+// webhooks for CertificateImport and CertificateExport, and the conversion
+// webhook between the v1 and v2 CertificateImport, on the manager's webhook
+// server.
+func RegisterWebhooksWithManager(mgr ctrl.Manager) {
+	mgr.GetWebhookServer().Register(validatePath, &webhook.Admission{Handler: &admissionValidator{Reader: mgr.GetAPIReader()}})
+	mgr.GetWebhookServer().Register(mutatePath, &webhook.Admission{Handler: &admissionDefaulter{}})
+	mgr.GetWebhookServer().Register(convertPath, conversion.NewWebhookHandler(mgr.GetScheme()))
+}
+
+// SetAPIGroup serves the CRDs under group instead of DefaultAPIGroup, for
+// installations that publish them under their own domain. It must be called
+// before AddToScheme and before any controller starts.
+func SetAPIGroup(group string) error {
+	if errs := validation.IsDNS1123Subdomain(group); len(errs) > 0 {
+		return fmt.Errorf("invalid API group %q: %s", group, strings.Join(errs, ", "))
+	}
+	crdGroup = group
+	return nil
+}
+
+// SetDefaultSchedule makes schedule the schedule of imports and exports that
+// do not set spec.schedule, instead of DefaultSchedule. It must be a valid
+// cron expression without a timezone prefix, since it is combined with
+// spec.timezone, and be set before any controller starts.
+func SetDefaultSchedule(schedule string) error {
+	schedule = strings.TrimSpace(schedule)
+	if _, err := parseSchedule(schedule); err != nil {
+		return fmt.Errorf("invalid default schedule %q: %v", schedule, err)
+	}
+	if strings.HasPrefix(schedule, "CRON_TZ=") || strings.HasPrefix(schedule, "TZ=") {
+		return fmt.Errorf("invalid default schedule %q: must not have a timezone prefix, set spec.timezone on imports and exports instead", schedule)
+	}
+	defaultSchedule = schedule
+	return nil
+}
+
+// SetDefaultFromExport makes ref the export of imports that write a secret
+// without setting spec.fromExport. ref must name the export explicitly, as
+// <namespace>/<name> or cluster/<name>, since a bare name would resolve in
+// every import's own namespace. It must be set before any controller starts.
+func SetDefaultFromExport(ref string) error {
+	ref = strings.TrimSpace(ref)
+	if ref != "" {
+		kind, key, err := exportKind("", ref)
+		if err != nil {
+			return fmt.Errorf("invalid default export %q: %v", ref, err)
+		}
+		if kind == "CertificateExport" && key.Namespace == "" {
+			return fmt.Errorf("invalid default export %q: must be <namespace>/<name> or %s<name>", ref, clusterExportPrefix)
+		}
+	}
+	defaultFromExport = ref
+	return nil
+}
+
+// CheckDefaultFromExport verifies through r that the export set by
+// SetDefaultFromExport exists, so that a typo fails at startup rather than
+// every import relying on it.
+func CheckDefaultFromExport(ctx context.Context, r client.Reader) error {
+	if defaultFromExport == "" {
+		return nil
+	}
+	_, err := getExport(ctx, r, "", defaultFromExport)
+	return err
+}
+
+// AddToScheme registers the typed v1 and v2 APIs with s under the API group
+// set by SetAPIGroup.
+func AddToScheme(s *runtime.Scheme) error {
+	if crdGroup == certtrustv1.GroupVersion.Group {
+		if err := certtrustv1.AddToScheme(s); err != nil {
+			return err
+		}
+		return certtrustv2.AddToScheme(s)
+	}
+	b := &scheme.Builder{GroupVersion: schema.GroupVersion{Group: crdGroup, Version: crdVersion}}
+	b.Register(
+		&certtrustv1.CertificateExport{}, &certtrustv1.CertificateExportList{},
+		&certtrustv1.CertificateImport{}, &certtrustv1.CertificateImportList{},
+		&certtrustv1.ClusterCertificateExport{}, &certtrustv1.ClusterCertificateExportList{},
+	)
+	if err := b.AddToScheme(s); err != nil {
+		return err
+	}
+	b2 := &scheme.Builder{GroupVersion: schema.GroupVersion{Group: crdGroup, Version: certtrustv2.GroupVersion.Version}}
+	b2.Register(&certtrustv2.CertificateImport{}, &certtrustv2.CertificateImportList{})
+	return b2.AddToScheme(s)
+}
@@ -0,0 +1,69 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestSetDefaultFromExport(t *testing.T) {
+	t.Cleanup(func() { defaultFromExport = "" })
+	tests := []struct {
+		ref     string
+		want    string
+		wantErr bool
+	}{
+		{ref: "backend/app", want: "backend/app"},
+		{ref: " cluster/root-ca ", want: "cluster/root-ca"},
+		{ref: "", want: ""},
+		{ref: "app", wantErr: true},
+		{ref: "backend/", wantErr: true},
+		{ref: "cluster/", wantErr: true},
+	}
+	for _, tt := range tests {
+		defaultFromExport = "unchanged"
+		err := SetDefaultFromExport(tt.ref)
+		if tt.wantErr {
+			if err == nil || defaultFromExport != "unchanged" {
+				t.Errorf("SetDefaultFromExport(%q) = %v, default %q, want an error", tt.ref, err, defaultFromExport)
+			}
+			continue
+		}
+		if err != nil || defaultFromExport != tt.want {
+			t.Errorf("SetDefaultFromExport(%q) = %v, default %q, want %q", tt.ref, err, defaultFromExport, tt.want)
+		}
+	}
+}
+
+func TestCheckDefaultFromExport(t *testing.T) {
+	t.Cleanup(func() { defaultFromExport = "" })
+	_, c := newTestController(t, Options{}, newExport("backend", "app", "app-tls"))
+	ctx := context.Background()
+
+	if err := SetDefaultFromExport("backend/app"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckDefaultFromExport(ctx, c); err != nil {
+		t.Errorf("existing default export failed the check: %v", err)
+	}
+	if err := SetDefaultFromExport("backend/typo"); err != nil {
+		t.Fatal(err)
+	}
+	if err := CheckDefaultFromExport(ctx, c); !errors.Is(err, ErrExportNotFound) {
+		t.Errorf("got error %v for a missing default export, want %v", err, ErrExportNotFound)
+	}
+}
@@ -0,0 +1,129 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// defaultKubeconfigKey is the key of the kubeconfig in the secret named by
+// spec.remote.kubeconfigSecretRef when spec.remote.kubeconfigKey is unset.
+const defaultKubeconfigKey = "kubeconfig"
+
+// remoteBackoff tracks consecutive connection failures of a remote export.
+type remoteBackoff struct {
+	failures int
+	until    time.Time
+}
+
+// isRemoteExport reports whether the export pushes into another cluster.
+func isRemoteExport(exp *unstructured.Unstructured) bool {
+	return getString(exp.Object, "spec.remote.kubeconfigSecretRef") != ""
+}
+
+// remoteClient builds a client for the cluster described by the kubeconfig
+// secret of a remote export. The secret lives in the export's namespace.
+func (s *SyncController) remoteClient(ctx context.Context, exp *unstructured.Unstructured) (client.Client, error) {
+	key := types.NamespacedName{Namespace: exp.GetNamespace(), Name: getString(exp.Object, "spec.remote.kubeconfigSecretRef")}
+	dataKey := getString(exp.Object, "spec.remote.kubeconfigKey")
+	if dataKey == "" {
+		dataKey = defaultKubeconfigKey
+	}
+	var sec corev1.Secret
+	if err := s.Get(ctx, key, &sec); err != nil {
+		return nil, err
+	}
+	raw, ok := sec.Data[dataKey]
+	if !ok {
+		return nil, fmt.Errorf("kubeconfig secret %s has no key %q", key, dataKey)
+	}
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(raw)
+	if err != nil {
+		return nil, fmt.Errorf("invalid kubeconfig in secret %s: %w", key, err)
+	}
+	newClient := client.New
+	if s.newRemoteClient != nil {
+		newClient = s.newRemoteClient
+	}
+	c, err := newClient(cfg, client.Options{Scheme: s.scheme})
+	if err != nil {
+		return nil, err
+	}
+	if s.opts.DryRun {
+		c = newDryRunClient(c)
+	}
+	return c, nil
+}
+
+// isUnreachable reports whether err means the remote API server could not be
+// reached, as opposed to it rejecting a request.
+func isUnreachable(err error) bool {
+	var status apierrors.APIStatus
+	return !errors.As(err, &status) || apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsServiceUnavailable(err)
+}
+
+// remoteBackoffRemaining returns how long pushes of a remote export are still
+// held back after connection failures.
+func (s *SyncController) remoteBackoffRemaining(key string) time.Duration {
+	s.remoteMu.Lock()
+	defer s.remoteMu.Unlock()
+	if b := s.remoteBackoffs[key]; b != nil {
+		return time.Until(b.until)
+	}
+	return 0
+}
+
+// remoteUnreachable backs off further pushes of a remote export
+// exponentially and sets its RemoteUnreachable condition.
+func (s *SyncController) remoteUnreachable(ctx context.Context, exp *unstructured.Unstructured, err error) {
+	key := exp.GetNamespace() + "/" + exp.GetName()
+	s.remoteMu.Lock()
+	b := s.remoteBackoffs[key]
+	if b == nil {
+		b = &remoteBackoff{}
+		s.remoteBackoffs[key] = b
+	}
+	b.failures++
+	failures := b.failures
+	delay := retryDelay(failures)
+	b.until = time.Now().Add(delay)
+	s.remoteMu.Unlock()
+
+	log.FromContext(ctx).Info("remote cluster unreachable, backing off", "export", key, "failures", failures, "backoff", delay)
+	msg := fmt.Sprintf("%v; next attempt in %s", err, delay)
+	_ = s.updateStatus(ctx, exp, func(exp *unstructured.Unstructured) bool {
+		return setCondition(exp, conditionRemoteUnreachable, metav1.ConditionTrue, reasonConnectionFailed, msg)
+	})
+}
+
+// remoteReachable clears the backoff of a remote export. The caller clears
+// its RemoteUnreachable condition.
+func (s *SyncController) remoteReachable(exp *unstructured.Unstructured) {
+	s.remoteMu.Lock()
+	defer s.remoteMu.Unlock()
+	delete(s.remoteBackoffs, exp.GetNamespace()+"/"+exp.GetName())
+}
@@ -0,0 +1,121 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"syscall"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+const testKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: workload
+  cluster:
+    server: https://workload.example:6443
+users:
+- name: pusher
+  user:
+    token: secret
+contexts:
+- name: workload
+  context:
+    cluster: workload
+    user: pusher
+current-context: workload
+`
+
+// newRemoteTest returns a controller whose remote export backend/ca pushes
+// into the api namespace of remote, and the local client.
+func newRemoteTest(t *testing.T, remote client.Client) (*SyncController, client.Client) {
+	t.Helper()
+	crt, key := newKeyPair(t, "ca")
+	exp := newPushExport("backend", "ca", "ca-tls", "ca-tls", "api")
+	setString(exp.Object, "spec.remote.kubeconfigSecretRef", "workload-kubeconfig")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newSecret("backend", "workload-kubeconfig", corev1.SecretTypeOpaque, map[string][]byte{defaultKubeconfigKey: []byte(testKubeconfig)}),
+		exp,
+	)
+	s.newRemoteClient = func(cfg *rest.Config, _ client.Options) (client.Client, error) {
+		if cfg.Host != "https://workload.example:6443" {
+			return nil, fmt.Errorf("got host %q, want the one of the kubeconfig secret", cfg.Host)
+		}
+		return remote, nil
+	}
+	return s, c
+}
+
+func TestSyncExportPushRemote(t *testing.T) {
+	remote := fake.NewClientBuilder().WithScheme(newTestScheme(t)).Build()
+	s, c := newRemoteTest(t, remote)
+	if err := s.syncExportPush(context.Background(), "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	pushed := getSecret(t, remote, "api", "ca-tls")
+	if pushed == nil {
+		t.Fatal("no secret was pushed to the remote cluster")
+	}
+	if pushed.Annotations[pushedByAnnotation] != "backend/ca" {
+		t.Errorf("got pushed-by %q, want backend/ca", pushed.Annotations[pushedByAnnotation])
+	}
+	if src := getSecret(t, c, "backend", "ca-tls"); string(pushed.Data[corev1.TLSCertKey]) != string(src.Data[corev1.TLSCertKey]) {
+		t.Error("the remote secret does not hold the local source")
+	}
+	if getSecret(t, c, "api", "ca-tls") != nil {
+		t.Error("a remote export pushed into the local cluster")
+	}
+	if getSecret(t, remote, "backend", "ca-tls") != nil {
+		t.Error("the source was copied to the remote cluster as is")
+	}
+}
+
+func TestSyncExportPushRemoteUnreachable(t *testing.T) {
+	refused := &url.Error{Op: "Get", URL: "https://workload.example:6443", Err: syscall.ECONNREFUSED}
+	remote := fake.NewClientBuilder().WithScheme(newTestScheme(t)).WithInterceptorFuncs(interceptor.Funcs{
+		Get: func(context.Context, client.WithWatch, client.ObjectKey, client.Object, ...client.GetOption) error {
+			return refused
+		},
+		Create: func(context.Context, client.WithWatch, client.Object, ...client.CreateOption) error {
+			return refused
+		},
+	}).Build()
+	s, c := newRemoteTest(t, remote)
+	ctx := context.Background()
+	if err := s.syncExportPush(ctx, "backend", "ca"); err == nil {
+		t.Fatal("push into an unreachable cluster succeeded")
+	}
+	cond := getExportCondition(t, c, "backend", "ca", conditionRemoteUnreachable)
+	if cond == nil || cond.Status != metav1.ConditionTrue {
+		t.Fatalf("got RemoteUnreachable %v, want it set", cond)
+	}
+	if wait := s.remoteBackoffRemaining("backend/ca"); wait <= 0 {
+		t.Fatal("further pushes are not held back")
+	}
+	// held back pushes don't try the remote cluster at all
+	if err := s.syncExportPush(ctx, "backend", "ca"); err != nil {
+		t.Errorf("held back push: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"syscall"
+	"time"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	// retryBaseDelay is the delay before the first retry of a failed sync.
+	retryBaseDelay = 10 * time.Second
+	// retryMaxDelay caps the exponential backoff between retries.
+	retryMaxDelay = 10 * time.Minute
+)
+
+// errSyncInProgress is returned by runImportSync when it skips a sync because
+// another one of the same import is still running.
+var errSyncInProgress = errors.New("import sync already in progress")
+
+// retryState tracks the retries of one import.
+type retryState struct {
+	failures int
+	timer    *time.Timer
+	running  bool
+}
+
+// retryDelay returns the backoff after the given number of consecutive
+// failures: retryBaseDelay doubled per failure, capped at retryMaxDelay.
+func retryDelay(failures int) time.Duration {
+	d := retryBaseDelay
+	for i := 1; i < failures; i++ {
+		d *= 2
+		if d >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return d
+}
+
+// isTransient reports whether a failed sync may succeed when retried as is:
+// API server timeouts, throttling, conflicts and unavailability, and broken
+// connections. Anything else, such as a missing export or an unauthorized
+// namespace, needs a change to the objects involved first.
+func isTransient(err error) bool {
+	if apierrors.IsTimeout(err) || apierrors.IsServerTimeout(err) || apierrors.IsTooManyRequests(err) ||
+		apierrors.IsConflict(err) || apierrors.IsServiceUnavailable(err) || apierrors.IsInternalError(err) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// runImportSync runs syncImport and, when it fails with a transient error,
+// schedules a retry with exponential backoff instead of waiting for the next
+// scheduled run. A run supersedes any pending retry, and a run is skipped
+// with errSyncInProgress while another one for the same import is in
+// progress, so retries never pile up.
+func (s *SyncController) runImportSync(ctx context.Context, namespace, name string) error {
+	key := namespace + "/" + name
+	s.retryMu.Lock()
+	st := s.retries[key]
+	if st == nil {
+		st = &retryState{}
+		s.retries[key] = st
+	}
+	if st.running {
+		s.retryMu.Unlock()
+		log.FromContext(ctx).V(1).Info("import sync already in progress, skipping", "import", key)
+		return errSyncInProgress
+	}
+	if st.timer != nil {
+		st.timer.Stop()
+		st.timer = nil
+	}
+	st.running = true
+	s.retryMu.Unlock()
+
+	err := s.syncImport(ctx, namespace, name)
+
+	s.retryMu.Lock()
+	st.running = false
+	if err == nil {
+		delete(s.retries, key)
+		s.retryMu.Unlock()
+		return nil
+	}
+	if !isTransient(err) {
+		// retrying can't help; the next scheduled run or a change of the
+		// objects involved syncs the import again
+		delete(s.retries, key)
+		s.retryMu.Unlock()
+		s.setRetryStatus(ctx, namespace, name, 0)
+		return err
+	}
+	st.failures++
+	failures := st.failures
+	delay := retryDelay(failures)
+	st.timer = time.AfterFunc(delay, func() {
+		logger := log.FromContext(context.Background())
+		logger.Info("retrying import sync", "import", key)
+		if err := s.runImportSync(context.Background(), namespace, name); err != nil && !errors.Is(err, errSyncInProgress) {
+			logger.Error(err, "retry of import sync failed", "import", key)
+		}
+	})
+	s.retryMu.Unlock()
+
+	log.FromContext(ctx).Info("scheduled retry of failed import sync", "import", key, "failures", failures, "backoff", delay)
+	if !s.setRetryStatus(ctx, namespace, name, delay) {
+		// the import is gone, there is nothing left to retry
+		s.forgetRetries(key)
+	}
+	return err
+}
+
+// setRetryStatus records the current backoff in status.retryBackoff
+// (best-effort), or clears it for a delay of 0 as no retry is pending, and
+// reports whether the import still exists. A successful sync clears the
+// field too.
+func (s *SyncController) setRetryStatus(ctx context.Context, namespace, name string, delay time.Duration) bool {
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, imp); err != nil {
+		return !apierrors.IsNotFound(err)
+	}
+	_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		if delay == 0 {
+			if getString(imp.Object, "status.retryBackoff") == "" {
+				return false
+			}
+			unstructured.RemoveNestedField(imp.Object, "status", "retryBackoff")
+			return true
+		}
+		setString(imp.Object, "status.retryBackoff", delay.String())
+		return true
+	})
+	return true
+}
+
+// forgetRetries cancels any pending retry of an import, e.g. once it is deleted.
+func (s *SyncController) forgetRetries(key string) {
+	s.retryMu.Lock()
+	defer s.retryMu.Unlock()
+	if st := s.retries[key]; st != nil && st.timer != nil {
+		st.timer.Stop()
+	}
+	delete(s.retries, key)
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"syscall"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+func TestRetryDelay(t *testing.T) {
+	tests := []struct {
+		failures int
+		want     time.Duration
+	}{
+		{failures: 1, want: 10 * time.Second},
+		{failures: 2, want: 20 * time.Second},
+		{failures: 3, want: 40 * time.Second},
+		{failures: 6, want: 320 * time.Second},
+		{failures: 7, want: retryMaxDelay},
+		{failures: 1000, want: retryMaxDelay},
+	}
+	for _, tt := range tests {
+		if got := retryDelay(tt.failures); got != tt.want {
+			t.Errorf("retryDelay(%d) = %s, want %s", tt.failures, got, tt.want)
+		}
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	gr := schema.GroupResource{Group: "cert.trust.flolive.io", Resource: "certificateexports"}
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "timeout", err: apierrors.NewTimeoutError("timed out", 1), want: true},
+		{name: "server timeout", err: apierrors.NewServerTimeout(gr, "get", 1), want: true},
+		{name: "too many requests", err: apierrors.NewTooManyRequests("slow down", 1), want: true},
+		{name: "conflict", err: apierrors.NewConflict(gr, "app", errors.New("modified")), want: true},
+		{name: "service unavailable", err: apierrors.NewServiceUnavailable("unavailable"), want: true},
+		{name: "internal error", err: apierrors.NewInternalError(errors.New("boom")), want: true},
+		{name: "connection refused", err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), want: true},
+		{name: "network error", err: &net.OpError{Op: "dial", Err: errors.New("no route to host")}, want: true},
+		{name: "eof", err: fmt.Errorf("read: %w", io.EOF), want: true},
+		{name: "export not found", err: fmt.Errorf("%w: backend/app: %w", ErrExportNotFound, apierrors.NewNotFound(gr, "app"))},
+		{name: "not authorized", err: fmt.Errorf("%w: frontend", ErrNotAuthorized)},
+		{name: "cyclic reference", err: ErrCyclicReference},
+		{name: "invalid reference", err: ErrInvalidReference},
+		{name: "target not managed", err: ErrTargetNotManaged},
+		{name: "wrong secret type", err: ErrWrongSecretType},
+		{name: "shutting down", err: errShuttingDown},
+	}
+	for _, tt := range tests {
+		if got := isTransient(tt.err); got != tt.want {
+			t.Errorf("%s: isTransient(%v) = %t, want %t", tt.name, tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestRunImportSyncBackoff(t *testing.T) {
+	// reads of the export time out until the third run
+	unavailable := true
+	crt, key := newKeyPair(t, "app")
+	s, c := newInterceptedTestController(t, Options{}, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if unavailable && obj.GetObjectKind().GroupVersionKind().Kind == "CertificateExport" {
+				return apierrors.NewTimeoutError("timed out reading export", 1)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	t.Cleanup(func() { s.forgetRetries("frontend/app") })
+	ctx := context.Background()
+	retryBackoff := func() string {
+		imp := &unstructured.Unstructured{}
+		imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "frontend", Name: "app"}, imp); err != nil {
+			t.Fatal(err)
+		}
+		return getString(imp.Object, "status.retryBackoff")
+	}
+
+	for _, want := range []string{"10s", "20s"} {
+		if err := s.runImportSync(ctx, "frontend", "app"); !apierrors.IsTimeout(err) {
+			t.Fatalf("got error %v, want a timeout", err)
+		}
+		if got := retryBackoff(); got != want {
+			t.Errorf("got retryBackoff %q, want %q", got, want)
+		}
+	}
+
+	unavailable = false
+	if err := s.runImportSync(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	s.retryMu.Lock()
+	_, pending := s.retries["frontend/app"]
+	s.retryMu.Unlock()
+	if pending {
+		t.Error("retry state was kept after a successful sync")
+	}
+	if got := retryBackoff(); got != "" {
+		t.Errorf("got retryBackoff %q after a successful sync, want it cleared", got)
+	}
+}
+
+func TestRunImportSyncPermanentError(t *testing.T) {
+	// the export times out once, then turns out to be gone
+	unavailable := true
+	s, c := newInterceptedTestController(t, Options{}, interceptor.Funcs{
+		Get: func(ctx context.Context, c client.WithWatch, key client.ObjectKey, obj client.Object, opts ...client.GetOption) error {
+			if unavailable && obj.GetObjectKind().GroupVersionKind().Kind == "CertificateExport" {
+				return apierrors.NewTimeoutError("timed out reading export", 1)
+			}
+			return c.Get(ctx, key, obj, opts...)
+		},
+	},
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	t.Cleanup(func() { s.forgetRetries("frontend/app") })
+	ctx := context.Background()
+	pending := func() bool {
+		s.retryMu.Lock()
+		defer s.retryMu.Unlock()
+		st := s.retries["frontend/app"]
+		return st != nil && st.timer != nil
+	}
+
+	if err := s.runImportSync(ctx, "frontend", "app"); !apierrors.IsTimeout(err) {
+		t.Fatalf("got error %v, want a timeout", err)
+	}
+	if !pending() {
+		t.Fatal("no retry was scheduled after a timeout")
+	}
+
+	unavailable = false
+	if err := s.runImportSync(ctx, "frontend", "app"); !errors.Is(err, ErrExportNotFound) {
+		t.Fatalf("got error %v, want ErrExportNotFound", err)
+	}
+	if pending() {
+		t.Error("a retry is pending after a sync failed for a missing export")
+	}
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "frontend", Name: "app"}, imp); err != nil {
+		t.Fatal(err)
+	}
+	if got := getString(imp.Object, "status.retryBackoff"); got != "" {
+		t.Errorf("got retryBackoff %q without a pending retry, want it cleared", got)
+	}
+}
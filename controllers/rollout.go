@@ -0,0 +1,92 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
+)
+
+// restartedAtAnnotation is set on the pod template of the rollout targets of
+// an import to the time its target secret changed, which rolls their pods the
+// same way kubectl rollout restart does.
+const restartedAtAnnotation = annotationPrefix + "restartedAt"
+
+// workloadFor returns an empty object of the kind a rollout target names.
+func workloadFor(kind string) (client.Object, error) {
+	switch kind {
+	case "Deployment":
+		return &appsv1.Deployment{}, nil
+	case "StatefulSet":
+		return &appsv1.StatefulSet{}, nil
+	}
+	return nil, fmt.Errorf("unsupported rollout target kind %q: must be Deployment or StatefulSet", kind)
+}
+
+// restartWorkloads bumps restartedAtAnnotation on the pod template of every
+// rollout target of imp. It is best-effort: the target secret is already
+// written, so failures are logged and recorded as events instead of failing
+// the sync, which would not restart anything on retry.
+func (s *SyncController) restartWorkloads(ctx context.Context, imp *unstructured.Unstructured, targets []certtrustv1.WorkloadRef) {
+	logger := log.FromContext(ctx)
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, ref := range targets {
+		if err := s.restartWorkload(ctx, imp.GetNamespace(), ref, now); err != nil {
+			logger.Error(err, "failed to restart rollout target", "kind", ref.Kind, "name", ref.Name)
+			if s.recorder != nil {
+				s.recorder.Eventf(imp, corev1.EventTypeWarning, eventReasonRolloutFailed, "failed to restart %s %s: %v", ref.Kind, ref.Name, err)
+			}
+			continue
+		}
+		logger.Info("restarted rollout target", "kind", ref.Kind, "name", ref.Name)
+		if s.recorder != nil {
+			s.recorder.Eventf(imp, corev1.EventTypeNormal, eventReasonRolloutRestarted, "restarted %s %s", ref.Kind, ref.Name)
+		}
+	}
+}
+
+// restartWorkload patches the pod template annotations of a single workload.
+func (s *SyncController) restartWorkload(ctx context.Context, namespace string, ref certtrustv1.WorkloadRef, now string) error {
+	obj, err := workloadFor(ref.Kind)
+	if err != nil {
+		return err
+	}
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: ref.Name}, obj); err != nil {
+		return err
+	}
+	patch := client.MergeFrom(obj.DeepCopyObject().(client.Object))
+	var tmpl *corev1.PodTemplateSpec
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		tmpl = &w.Spec.Template
+	case *appsv1.StatefulSet:
+		tmpl = &w.Spec.Template
+	}
+	if tmpl.Annotations == nil {
+		tmpl.Annotations = map[string]string{}
+	}
+	tmpl.Annotations[restartedAtAnnotation] = now
+	return s.Patch(ctx, obj, patch)
+}
@@ -0,0 +1,125 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
+)
+
+// restartedAt returns the restartedAtAnnotation of the pod template of the
+// workload obj names.
+func restartedAt(t *testing.T, c client.Client, obj client.Object) string {
+	t.Helper()
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(obj), obj); err != nil {
+		t.Fatal(err)
+	}
+	switch w := obj.(type) {
+	case *appsv1.Deployment:
+		return w.Spec.Template.Annotations[restartedAtAnnotation]
+	case *appsv1.StatefulSet:
+		return w.Spec.Template.Annotations[restartedAtAnnotation]
+	}
+	return ""
+}
+
+func TestSyncImportRestartsRolloutTargets(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls", "rolloutTargets": []interface{}{
+			map[string]interface{}{"kind": "Deployment", "name": "web"},
+			map[string]interface{}{"kind": "StatefulSet", "name": "db"},
+			map[string]interface{}{"kind": "Deployment", "name": "missing"},
+		}}),
+		&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "frontend", Name: "web"}},
+		&appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "frontend", Name: "db"}},
+	)
+	recorder := record.NewFakeRecorder(20)
+	s.recorder = recorder
+	ctx := context.Background()
+	web := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Namespace: "frontend", Name: "web"}}
+	db := &appsv1.StatefulSet{ObjectMeta: metav1.ObjectMeta{Namespace: "frontend", Name: "db"}}
+
+	// the first sync creates the target, which nothing mounts yet, and an
+	// unchanged resync writes nothing
+	for i := 0; i < 2; i++ {
+		if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if restartedAt(t, c, web) != "" || restartedAt(t, c, db) != "" {
+		t.Fatal("restarted the workloads without a change of content")
+	}
+
+	renewed, renewedKey := newKeyPair(t, "app")
+	src := getSecret(t, c, "backend", "app-tls")
+	src.Data = map[string][]byte{corev1.TLSCertKey: renewed, corev1.TLSPrivateKeyKey: renewedKey}
+	if err := c.Update(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	// drain the events of the earlier syncs
+	for len(recorder.Events) > 0 {
+		<-recorder.Events
+	}
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatalf("a failing rollout target failed the sync: %v", err)
+	}
+	if restartedAt(t, c, web) == "" || restartedAt(t, c, db) == "" {
+		t.Error("the rollout targets were not restarted after the content changed")
+	}
+	var restarted, failed int
+	for len(recorder.Events) > 0 {
+		switch got := <-recorder.Events; {
+		case strings.HasPrefix(got, corev1.EventTypeNormal+" "+eventReasonRolloutRestarted+" "):
+			restarted++
+		case strings.HasPrefix(got, corev1.EventTypeWarning+" "+eventReasonRolloutFailed+" "):
+			failed++
+		}
+	}
+	if restarted != 2 || failed != 1 {
+		t.Errorf("got %d RolloutRestarted and %d RolloutFailed events, want 2 and 1", restarted, failed)
+	}
+
+	// a later unchanged sync leaves the annotation alone
+	web.Spec.Template.Annotations[restartedAtAnnotation] = "unchanged"
+	if err := c.Update(ctx, web); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if got := restartedAt(t, c, web); got != "unchanged" {
+		t.Errorf("got restartedAt %q after an unchanged sync, want it untouched", got)
+	}
+}
+
+func TestRestartWorkloadUnsupportedKind(t *testing.T) {
+	s, _ := newTestController(t, Options{})
+	err := s.restartWorkload(context.Background(), "frontend", certtrustv1.WorkloadRef{Kind: "DaemonSet", Name: "agent"}, "now")
+	if err == nil || !strings.Contains(err.Error(), "unsupported rollout target kind") {
+		t.Errorf("got error %v, want an unsupported kind", err)
+	}
+}
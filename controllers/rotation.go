@@ -0,0 +1,98 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// indexSources rebuilds the reverse index from source secret to the imports
+// copying from it. It runs on every pass of the reschedule loop, so new,
+// changed and deleted imports and exports are reflected within
+// Options.RescheduleInterval.
+func (s *SyncController) indexSources(imports, exports []unstructured.Unstructured) {
+	importers := indexImporters(imports)
+	index := map[types.NamespacedName][]types.NamespacedName{}
+	for i := range exports {
+		exp := &exports[i]
+		src := exportSource(exp)
+		index[src] = append(index[src], importers[exportID(exp.GetKind(), client.ObjectKeyFromObject(exp))]...)
+	}
+	s.sourceMu.Lock()
+	s.sourceIndex = index
+	s.sourceMu.Unlock()
+}
+
+// sourceSecretImports maps a source secret to every import copying from it.
+func (s *SyncController) sourceSecretImports(_ context.Context, obj client.Object) []reconcile.Request {
+	s.sourceMu.RLock()
+	defer s.sourceMu.RUnlock()
+	imports := s.sourceIndex[types.NamespacedName{Namespace: obj.GetNamespace(), Name: obj.GetName()}]
+	reqs := make([]reconcile.Request, 0, len(imports))
+	for _, key := range imports {
+		reqs = append(reqs, reconcile.Request{NamespacedName: key})
+	}
+	return reqs
+}
+
+// rotationPredicate passes secrets that are created or whose data or type
+// changed. Metadata-only updates, such as the status writes of other
+// controllers, are ignored.
+func rotationPredicate() predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(event.CreateEvent) bool { return true },
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSec, ok := e.ObjectOld.(*corev1.Secret)
+			newSec, ok2 := e.ObjectNew.(*corev1.Secret)
+			if !ok || !ok2 {
+				return false
+			}
+			return oldSec.Type != newSec.Type || !reflect.DeepEqual(oldSec.Data, newSec.Data)
+		},
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// rotationReconciler re-syncs the imports of a source secret as soon as it
+// changes, e.g. when cert-manager renews it, instead of waiting for their
+// next scheduled run.
+type rotationReconciler struct {
+	s *SyncController
+}
+
+func (r *rotationReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	logger := log.FromContext(ctx)
+	logger.Info("source secret changed, syncing import", "import", req.String())
+	// runImportSync schedules its own retries; requeueing here would double
+	// them. A sync already in progress may have read the old source, though.
+	if err := r.s.runImportSync(ctx, req.Namespace, req.Name); errors.Is(err, errSyncInProgress) {
+		return reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		logger.Error(err, "failed to sync import", "import", req.String())
+	}
+	return reconcile.Result{}, nil
+}
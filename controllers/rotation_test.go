@@ -0,0 +1,114 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"bytes"
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestRotationPredicate(t *testing.T) {
+	data := map[string][]byte{corev1.TLSCertKey: []byte("crt")}
+	old := newSecret("backend", "app-tls", corev1.SecretTypeTLS, data)
+	relabeled := old.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "web"}
+	tests := []struct {
+		name string
+		sec  *corev1.Secret
+		want bool
+	}{
+		{name: "metadata only", sec: relabeled},
+		{name: "rotated", sec: newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: []byte("renewed")}), want: true},
+		{name: "retyped", sec: newSecret("backend", "app-tls", corev1.SecretTypeOpaque, data), want: true},
+	}
+	p := rotationPredicate()
+	for _, tt := range tests {
+		if got := p.Update(event.UpdateEvent{ObjectOld: old, ObjectNew: tt.sec}); got != tt.want {
+			t.Errorf("%s: got %t, want %t", tt.name, got, tt.want)
+		}
+	}
+	if !p.Create(event.CreateEvent{Object: old}) {
+		t.Error("creating a source secret was ignored")
+	}
+	if p.Delete(event.DeleteEvent{Object: old}) {
+		t.Error("deleting a source secret was passed")
+	}
+}
+
+func TestSourceSecretImports(t *testing.T) {
+	s, _ := newTestController(t, Options{})
+	s.indexSources([]unstructured.Unstructured{
+		*newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		*newImport("web", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		*newImport("web", "other", map[string]interface{}{"fromExport": "backend/other", "targetSecret": "other-tls"}),
+	}, []unstructured.Unstructured{
+		*newExport("backend", "app", "app-tls"),
+		*newExport("backend", "other", "other-tls"),
+	})
+	reqs := s.sourceSecretImports(context.Background(), newSecret("backend", "app-tls", corev1.SecretTypeTLS, nil))
+	got := map[types.NamespacedName]bool{}
+	for _, req := range reqs {
+		got[req.NamespacedName] = true
+	}
+	if len(got) != 2 || !got[types.NamespacedName{Namespace: "frontend", Name: "app"}] || !got[types.NamespacedName{Namespace: "web", Name: "app"}] {
+		t.Errorf("got requests %v, want frontend/app and web/app", reqs)
+	}
+	if reqs := s.sourceSecretImports(context.Background(), newSecret("backend", "unused", corev1.SecretTypeTLS, nil)); len(reqs) != 0 {
+		t.Errorf("got requests %v for a secret no export reads", reqs)
+	}
+}
+
+func TestRotationReconcilerPropagatesRenewal(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx := context.Background()
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+
+	// cert-manager renews the source
+	renewed, renewedKey := newKeyPair(t, "app")
+	src := getSecret(t, c, "backend", "app-tls")
+	src.Data = map[string][]byte{corev1.TLSCertKey: renewed, corev1.TLSPrivateKeyKey: renewedKey}
+	if err := c.Update(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	r := &rotationReconciler{s: s}
+	res, err := r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "frontend", Name: "app"}})
+	if err != nil || res.Requeue {
+		t.Fatalf("got %+v, %v, want the sync done", res, err)
+	}
+	if got := getSecret(t, c, "frontend", "app-tls"); !bytes.Equal(got.Data[corev1.TLSCertKey], renewed) {
+		t.Error("the renewed certificate was not copied to the target")
+	}
+
+	// a sync of the import already running may have read the old source
+	s.retries["frontend/app"] = &retryState{running: true}
+	res, err = r.Reconcile(ctx, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "frontend", Name: "app"}})
+	if err != nil || !res.Requeue {
+		t.Errorf("got %+v, %v while a sync was running, want a requeue", res, err)
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ScheduleInfo describes the effective schedule of an import or export as
+// the controller would run it.
+type ScheduleInfo struct {
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Schedule is the cron expression in effect, after applying the default
+	// schedule and spec.timezone
+	Schedule string `json:"schedule"`
+	// Valid is false when the schedule does not parse, with the reason in
+	// Error
+	Valid bool   `json:"valid"`
+	Error string `json:"error,omitempty"`
+	// Suspended imports and exports are not run
+	Suspended bool `json:"suspended,omitempty"`
+	// JitterDelay is added to every run of an import
+	JitterDelay metav1.Duration `json:"jitterDelay,omitempty"`
+	// NextRuns are the next run times, jitter included
+	NextRuns []time.Time `json:"nextRuns,omitempty"`
+}
+
+// Schedules returns the effective schedule of every import and export in
+// namespace, or in all namespaces when empty, with up to n run times after
+// now. Cluster exports are included when they publish a ClusterTrustBundle,
+// the only ones that run on a schedule. opts supplies Options.SyncJitter.
+func Schedules(ctx context.Context, c client.Reader, opts Options, namespace string, n int, now time.Time) ([]ScheduleInfo, error) {
+	s := &SyncController{opts: opts}
+	var out []ScheduleInfo
+	for _, kind := range []string{"CertificateImport", "CertificateExport", "ClusterCertificateExport"} {
+		if kind == "ClusterCertificateExport" && namespace != "" {
+			continue
+		}
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(schemaGVKList(kind))
+		if err := c.List(ctx, list, client.InNamespace(namespace)); err != nil {
+			return nil, err
+		}
+		sortByNamespacedName(list.Items)
+		for i := range list.Items {
+			item := &list.Items[i]
+			if kind == "ClusterCertificateExport" && getString(item.Object, "spec.clusterTrustBundle.name") == "" {
+				continue
+			}
+			out = append(out, s.scheduleInfo(item, n, now))
+		}
+	}
+	return out, nil
+}
+
+// scheduleInfo evaluates the schedule of obj with the same parsing as
+// buildSchedules.
+func (s *SyncController) scheduleInfo(obj *unstructured.Unstructured, n int, now time.Time) ScheduleInfo {
+	info := ScheduleInfo{Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName(), Suspended: isSuspended(obj)}
+	schedule, err := scheduleSpec(obj)
+	if err != nil {
+		info.Schedule, info.Error = getString(obj.Object, "spec.schedule"), err.Error()
+		return info
+	}
+	info.Schedule = schedule
+	sched, err := parseSchedule(schedule)
+	if err != nil {
+		info.Error = err.Error()
+		return info
+	}
+	if info.Kind == "CertificateImport" {
+		imp, err := toImport(obj)
+		if err == nil {
+			var jitter time.Duration
+			if jitter, err = s.importJitter(imp); err == nil {
+				info.JitterDelay = metav1.Duration{Duration: jitterDelay(string(imp.UID), jitter)}
+			}
+		}
+		if err != nil {
+			info.Error = err.Error()
+			return info
+		}
+	}
+	info.Valid = true
+	next := now
+	for i := 0; i < n; i++ {
+		next = sched.Next(next)
+		info.NextRuns = append(info.NextRuns, next.Add(info.JitterDelay.Duration))
+	}
+	return info
+}
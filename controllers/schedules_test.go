@@ -0,0 +1,114 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSchedules(t *testing.T) {
+	withSchedule := func(spec map[string]interface{}, schedule, tz string) map[string]interface{} {
+		spec["schedule"] = schedule
+		if tz != "" {
+			spec["timezone"] = tz
+		}
+		return spec
+	}
+	imp := func(name string) map[string]interface{} {
+		return map[string]interface{}{"fromExport": "backend/app", "targetSecret": name + "-tls"}
+	}
+	suspended := imp("paused")
+	suspended["suspend"] = true
+	exp := newExport("backend", "app", "app-tls")
+	setString(exp.Object, "spec.schedule", "0 6 * * *")
+	setString(exp.Object, "spec.timezone", "UTC")
+	_, c := newTestController(t, Options{},
+		newImport("frontend", "hourly", withSchedule(imp("hourly"), "0 * * * *", "UTC")),
+		newImport("frontend", "broken", withSchedule(imp("broken"), "every day", "")),
+		newImport("frontend", "unknown-tz", withSchedule(imp("unknown-tz"), "0 * * * *", "Mars/Olympus")),
+		newImport("frontend", "paused", withSchedule(suspended, "30 * * * *", "UTC")),
+		newImport("web", "other", withSchedule(imp("other"), "0 * * * *", "UTC")),
+		exp,
+	)
+	now := time.Date(2025, 6, 1, 10, 15, 0, 0, time.UTC)
+	infos, err := Schedules(context.Background(), c, Options{}, "", 2, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string]ScheduleInfo{}
+	var order []string
+	for _, info := range infos {
+		key := info.Kind + " " + info.Namespace + "/" + info.Name
+		byName[key] = info
+		order = append(order, key)
+	}
+	wantOrder := []string{
+		"CertificateImport frontend/broken",
+		"CertificateImport frontend/hourly",
+		"CertificateImport frontend/paused",
+		"CertificateImport frontend/unknown-tz",
+		"CertificateImport web/other",
+		"CertificateExport backend/app",
+	}
+	if !reflect.DeepEqual(order, wantOrder) {
+		t.Fatalf("got schedules %q, want %q", order, wantOrder)
+	}
+
+	hourly := byName["CertificateImport frontend/hourly"]
+	wantRuns := []time.Time{time.Date(2025, 6, 1, 11, 0, 0, 0, time.UTC), time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)}
+	if !hourly.Valid || hourly.Schedule != "CRON_TZ=UTC 0 * * * *" || !equalTimes(hourly.NextRuns, wantRuns) {
+		t.Errorf("got %+v for the hourly import, want valid runs %v", hourly, wantRuns)
+	}
+	if paused := byName["CertificateImport frontend/paused"]; !paused.Valid || !paused.Suspended {
+		t.Errorf("got %+v for the suspended import, want a valid suspended schedule", paused)
+	}
+	for _, key := range []string{"CertificateImport frontend/broken", "CertificateImport frontend/unknown-tz"} {
+		if info := byName[key]; info.Valid || info.Error == "" || len(info.NextRuns) != 0 {
+			t.Errorf("got %+v for %s, want an invalid schedule with its error", info, key)
+		}
+	}
+	if info := byName["CertificateImport frontend/unknown-tz"]; !strings.Contains(info.Error, "Mars/Olympus") {
+		t.Errorf("got error %q, want it to name the timezone", info.Error)
+	}
+	export := byName["CertificateExport backend/app"]
+	if want := time.Date(2025, 6, 2, 6, 0, 0, 0, time.UTC); !export.Valid || len(export.NextRuns) != 2 || !export.NextRuns[0].Equal(want) {
+		t.Errorf("got %+v for the export, want its first run at %v", export, want)
+	}
+
+	infos, err = Schedules(context.Background(), c, Options{}, "web", 1, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(infos) != 1 || infos[0].Name != "other" || len(infos[0].NextRuns) != 1 {
+		t.Errorf("got %+v for namespace web, want only web/other with one run", infos)
+	}
+}
+
+// equalTimes reports whether a and b hold the same instants.
+func equalTimes(a, b []time.Time) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if !a[i].Equal(b[i]) {
+			return false
+		}
+	}
+	return true
+}
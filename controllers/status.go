@@ -0,0 +1,48 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/util/retry"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// updateStatus applies mutate to obj and writes its status when mutate
+// reports a change. On a conflict obj is re-read and mutate applied again, so
+// concurrent status writers (e.g. a sync and the reschedule loop) don't drop
+// each other's changes. Failures that persist are logged and returned.
+func (s *SyncController) updateStatus(ctx context.Context, obj *unstructured.Unstructured, mutate func(*unstructured.Unstructured) bool) error {
+	first := true
+	err := retry.RetryOnConflict(retry.DefaultBackoff, func() error {
+		if !first {
+			if err := s.liveReader().Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+				return err
+			}
+		}
+		first = false
+		if !mutate(obj) {
+			return nil
+		}
+		return s.Status().Update(ctx, obj)
+	})
+	if err != nil {
+		log.FromContext(ctx).Error(err, "failed to update status", "kind", obj.GetKind(), "namespace", obj.GetNamespace(), "name", obj.GetName())
+	}
+	return err
+}
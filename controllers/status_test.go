@@ -0,0 +1,82 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// conflictingStatus returns interceptor funcs failing the first conflicts
+// status writes with a Conflict, as if another writer had won the race.
+func conflictingStatus(conflicts int, attempts *int) interceptor.Funcs {
+	return interceptor.Funcs{
+		SubResourceUpdate: func(ctx context.Context, c client.Client, subResource string, obj client.Object, opts ...client.SubResourceUpdateOption) error {
+			*attempts++
+			if *attempts <= conflicts {
+				return apierrors.NewConflict(schema.GroupResource{Group: DefaultAPIGroup, Resource: "certificateimports"}, obj.GetName(), nil)
+			}
+			return c.SubResource(subResource).Update(ctx, obj, opts...)
+		},
+	}
+}
+
+func TestSyncImportStatusConflict(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	var attempts int
+	s, c := newInterceptedTestController(t, Options{}, conflictingStatus(1, &attempts),
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	if err := s.syncImport(context.Background(), "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if attempts < 2 {
+		t.Fatalf("got %d status writes, want a retry after the conflict", attempts)
+	}
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: "frontend", Name: "app"}, imp); err != nil {
+		t.Fatal(err)
+	}
+	if getString(imp.Object, "status.lastSyncTime") == "" {
+		t.Error("lastSyncTime was not written after the conflict")
+	}
+}
+
+func TestUpdateStatusPersistentConflict(t *testing.T) {
+	var attempts int
+	imp := newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"})
+	s, _ := newInterceptedTestController(t, Options{}, conflictingStatus(100, &attempts), imp)
+	err := s.updateStatus(context.Background(), imp, func(imp *unstructured.Unstructured) bool {
+		setString(imp.Object, "status.lastSyncTime", "2025-06-01T10:00:00Z")
+		return true
+	})
+	if !apierrors.IsConflict(err) {
+		t.Errorf("got error %v, want the conflict once the retries are exhausted", err)
+	}
+	if attempts < 2 {
+		t.Errorf("got %d status writes, want retries", attempts)
+	}
+}
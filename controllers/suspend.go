@@ -0,0 +1,67 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// pausedAnnotation set to "true" on an import or export pauses it like
+// spec.suspend, e.g. with kubectl annotate during an incident.
+const pausedAnnotation = annotationPrefix + "paused"
+
+// isPaused reports whether the paused annotation is set on an import or export.
+func isPaused(obj *unstructured.Unstructured) bool {
+	return obj.GetAnnotations()[pausedAnnotation] == "true"
+}
+
+// isSuspended reports whether spec.suspend or the paused annotation is set on
+// an import or export.
+func isSuspended(obj *unstructured.Unstructured) bool {
+	return getBool(obj.Object, "spec.suspend", false) || isPaused(obj)
+}
+
+// filterSuspended drops suspended items so they are not scheduled, and
+// reflects the state of every item in its Suspended condition. A resumed
+// item loses the condition and is scheduled again on this pass.
+func (s *SyncController) filterSuspended(ctx context.Context, items []unstructured.Unstructured) []unstructured.Unstructured {
+	active := items[:0]
+	for i := range items {
+		item := items[i]
+		suspended := isSuspended(&item)
+		_ = s.updateStatus(ctx, &item, func(item *unstructured.Unstructured) bool {
+			if !suspended {
+				return removeCondition(item, conditionSuspended)
+			}
+			reason, msg := reasonSuspended, "syncing is paused by spec.suspend"
+			if isPaused(item) {
+				reason, msg = reasonPaused, "syncing is paused by the "+pausedAnnotation+" annotation"
+			}
+			changed := setCondition(item, conditionSuspended, metav1.ConditionTrue, reason, msg)
+			if _, ok, _ := unstructured.NestedString(item.Object, "status", "nextSyncTime"); ok {
+				unstructured.RemoveNestedField(item.Object, "status", "nextSyncTime")
+				changed = true
+			}
+			return changed
+		})
+		if !suspended {
+			active = append(active, item)
+		}
+	}
+	return active
+}
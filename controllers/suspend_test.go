@@ -0,0 +1,100 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestFilterSuspended(t *testing.T) {
+	active := newImport("frontend", "active", map[string]interface{}{"fromExport": "backend/app"})
+	suspended := newImport("frontend", "suspended", map[string]interface{}{"fromExport": "backend/app", "suspend": true})
+	setString(suspended.Object, "status.nextSyncTime", "2025-06-01T10:00:00Z")
+	resumed := newImport("frontend", "resumed", map[string]interface{}{"fromExport": "backend/app", "suspend": false})
+	setCondition(resumed, conditionSuspended, metav1.ConditionTrue, reasonSuspended, "syncing is paused by spec.suspend")
+	s, c := newTestController(t, Options{}, active, suspended, resumed)
+
+	got := s.filterSuspended(context.Background(), []unstructured.Unstructured{*active, *suspended, *resumed})
+	var names []string
+	for i := range got {
+		names = append(names, got[i].GetName())
+	}
+	if len(names) != 2 || names[0] != "active" || names[1] != "resumed" {
+		t.Errorf("got active imports %v, want [active resumed]", names)
+	}
+
+	tests := []struct {
+		name          string
+		wantSuspended bool
+	}{
+		{name: "active"},
+		{name: "suspended", wantSuspended: true},
+		{name: "resumed"},
+	}
+	for _, tt := range tests {
+		cond := getImportCondition(t, c, "frontend", tt.name, conditionSuspended)
+		if got := cond != nil && cond.Status == metav1.ConditionTrue; got != tt.wantSuspended {
+			t.Errorf("%s: got Suspended condition %v, want %t", tt.name, cond, tt.wantSuspended)
+		}
+	}
+	if cond := getImportCondition(t, c, "frontend", "suspended", conditionSuspended); cond != nil && cond.Reason != reasonSuspended {
+		t.Errorf("got reason %q, want %q", cond.Reason, reasonSuspended)
+	}
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := c.Get(context.Background(), client.ObjectKeyFromObject(suspended), imp); err != nil {
+		t.Fatal(err)
+	}
+	if next := getString(imp.Object, "status.nextSyncTime"); next != "" {
+		t.Errorf("got nextSyncTime %q on a suspended import, want it cleared", next)
+	}
+}
+
+func TestSyncExportPushSuspended(t *testing.T) {
+	crt, key := newKeyPair(t, "ca")
+	exp := newPushExport("backend", "ca", "ca-tls", "ca-tls", "api")
+	_ = unstructured.SetNestedField(exp.Object, true, "spec", "suspend")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		exp,
+	)
+	if err := s.syncExportPush(context.Background(), "backend", "ca"); err != nil {
+		t.Fatal(err)
+	}
+	if getSecret(t, c, "api", "ca-tls") != nil {
+		t.Error("a suspended export pushed its secret")
+	}
+}
+
+func TestSyncImportSuspended(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls", "suspend": true}),
+	)
+	if err := s.syncImport(context.Background(), "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if getSecret(t, c, "frontend", "app-tls") != nil {
+		t.Error("a suspended import wrote its target")
+	}
+}
@@ -17,79 +17,298 @@ package controllers
 import (
 	"context"
 	"crypto/sha256"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	cron "github.com/robfig/cron/v3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
 	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
 )
 
+// DefaultAPIGroup is the API group of the CRDs unless SetAPIGroup overrides it.
+const DefaultAPIGroup = "cert.trust.flolive.io"
+
+// crdGroup is the API group imports and exports are read and written under.
+var crdGroup = DefaultAPIGroup
+
 const (
-	crdGroup   = "cert.trust.flolive.io"
 	crdVersion = "v1"
+
+	// annotationPrefix is reserved for metadata the controller owns on target
+	// secrets; spec-provided labels/annotations under it are ignored.
+	annotationPrefix = "cert-trust.flolive.io/"
+	// managedLabelsAnnotation/managedAnnotationsAnnotation record the keys that
+	// were applied from spec.targetLabels/spec.targetAnnotations so they can be
+	// removed once dropped from the spec.
+	managedLabelsAnnotation      = annotationPrefix + "managed-labels"
+	managedAnnotationsAnnotation = annotationPrefix + "managed-annotations"
+	// managedKeysAnnotation records the data keys an import last wrote to its
+	// target secret, so keys no longer desired are removed while other keys
+	// of an adopted secret are left alone.
+	managedKeysAnnotation = annotationPrefix + "managed-keys"
+	// adoptAnnotation on a CertificateImport opts in to taking ownership of a
+	// pre-existing target secret that has no controller owner.
+	adoptAnnotation = annotationPrefix + "adopt"
+	// managedByAnnotation marks a target secret as written by the import named
+	// in its value (namespace/name).
+	managedByAnnotation = annotationPrefix + "managed-by"
+	// sourceExportAnnotation, sourceNamespaceAnnotation and
+	// sourceSecretAnnotation record where a target secret was copied from:
+	// the export (namespace/name, or cluster/name) and its source secret.
+	sourceExportAnnotation    = annotationPrefix + "source-export"
+	sourceNamespaceAnnotation = annotationPrefix + "source-namespace"
+	sourceSecretAnnotation    = annotationPrefix + "source-secret"
 )
 
 type SyncController struct {
+	// Client reads from the manager's cache: imports, exports, source
+	// secrets and namespaces are served from informers, not the API server
 	client.Client
-	scheme *runtime.Scheme
-	cron   *cron.Cron
-	// immediateOnStart controls whether to perform a one-time immediate sync
-	// after (re)building schedules. It is guarded by immediateOnce to ensure
-	// it triggers at most once per process lifetime.
-	immediateOnStart bool
-	immediateOnce    bool
+	// apiReader reads from the API server directly. It is used where a stale
+	// read would cause a conflicting write, see liveReader
+	apiReader client.Reader
+	scheme    *runtime.Scheme
+	recorder  record.EventRecorder
+	// informers, when set, is waited on before the immediate sync on start
+	informers cache.Informers
+	cron      *cron.Cron
+	opts      Options
+	// primed records the UIDs of imports already seen by primeImports, so
+	// each import gets at most one immediate sync.
+	primed map[types.UID]struct{}
+	// retries tracks the backoff of failed import syncs, keyed by namespace/name
+	retryMu sync.Mutex
+	retries map[string]*retryState
+	// remoteBackoffs holds back pushes of remote exports whose cluster was
+	// unreachable, keyed by namespace/name
+	remoteMu       sync.Mutex
+	remoteBackoffs map[string]*remoteBackoff
+	// newRemoteClient builds the client of a remote export from its
+	// kubeconfig; client.New when nil
+	newRemoteClient func(*rest.Config, client.Options) (client.Client, error)
+	// syncSlots bounds concurrent syncs under Options.MaxConcurrentSyncs;
+	// nil means unbounded
+	syncSlots chan struct{}
+	// running counts the syncs in flight by scheduleKey, so shutdown can
+	// wait for them; stopping is closed once shutdown begins
+	runningMu sync.Mutex
+	running   map[string]int
+	stopping  chan struct{}
+	// scheduled holds the cron entry of every scheduled import and push
+	// export, keyed by scheduleKey, so only changed entries are replaced
+	scheduled map[string]scheduledEntry
+	// sourceIndex maps source secrets to the imports copying from them, for
+	// Options.SyncOnSecretChange
+	sourceMu    sync.RWMutex
+	sourceIndex map[types.NamespacedName][]types.NamespacedName
+	// schedulesBuilt is set once buildSchedules succeeds and lastRebuildTime
+	// on every pass of the reschedule loop, for the health checks
+	healthMu        sync.Mutex
+	schedulesBuilt  bool
+	lastRebuildTime time.Time
 	// Track last known resource state to avoid unnecessary rebuilds
 	lastExportCount  int
 	lastImportCount  int
 	lastResourceHash string
 }
 
-func NewSyncController(c client.Client, scheme *runtime.Scheme, immediateOnStart bool) *SyncController {
-	return &SyncController{Client: c, scheme: scheme, cron: cron.New(), immediateOnStart: immediateOnStart}
+// Options configures a SyncController.
+type Options struct {
+	// ImmediateOnStart controls whether each import is synced immediately
+	// when it is first seen, rather than waiting for its schedule.
+	ImmediateOnStart bool
+	// ExpiryWarningThreshold is how long before the leaf certificate expires
+	// an import is flagged as ExpiringSoon. Zero disables the warning.
+	ExpiryWarningThreshold time.Duration
+	// SyncJitter delays each scheduled import sync by a stable, UID-derived
+	// amount up to this duration, unless the import sets spec.jitter.
+	SyncJitter time.Duration
+	// RescheduleInterval is how often schedules are rebuilt from the current
+	// imports and exports. Defaults to one minute.
+	RescheduleInterval time.Duration
+	// WatchNamespaces, when non-empty, limits the namespaces whose imports
+	// and exports are processed and whose secrets are written.
+	WatchNamespaces []string
+	// ExcludeNamespaces lists namespaces that are never processed or written,
+	// even when listed in WatchNamespaces.
+	ExcludeNamespaces []string
+	// Namespace, when set, confines the controller to that single namespace
+	// for installs with namespaced RBAC: cluster exports and namespace
+	// lookups are skipped, and imports may only reference exports in their
+	// own namespace. It implies WatchNamespaces of just Namespace.
+	Namespace string
+	// DryRun logs every write the controller would make, including which
+	// secret keys would change, without applying it.
+	DryRun bool
+	// MaxConcurrentSyncs bounds how many syncs, pushes and trust bundle
+	// publishes run at once; further ones wait for a free slot. Zero means
+	// no limit.
+	MaxConcurrentSyncs int
+	// WriteQPS and WriteBurst limit the writes to the cluster with a token
+	// bucket of WriteBurst tokens refilled at WriteQPS per second. A WriteQPS
+	// of zero means no limit.
+	WriteQPS   float64
+	WriteBurst int
+	// ClusterTrustBundles publishes the CA of ClusterCertificateExports that
+	// set spec.clusterTrustBundle as certificates.k8s.io/v1alpha1
+	// ClusterTrustBundles. Only takes effect when the API server serves them.
+	ClusterTrustBundles bool
+	// SyncOnSecretChange syncs the imports of a source secret as soon as its
+	// data changes, in addition to their schedule.
+	SyncOnSecretChange bool
+	// ShutdownTimeout bounds how long shutdown waits for syncs in flight to
+	// finish before abandoning them. Zero abandons them right away.
+	ShutdownTimeout time.Duration
+	// MaxSecretSize is the largest total size in bytes of the keys and
+	// values of a target secret that is written. 0 means no limit.
+	MaxSecretSize int
+	// GCOrphans deletes managed target secrets whose import no longer
+	// exists, and pushed secrets whose export no longer exists, on every
+	// pass of the reschedule loop.
+	GCOrphans bool
+	// DebugBindAddress, when set, is the address the read-only debug
+	// endpoints are served on.
+	DebugBindAddress string
+}
+
+func NewSyncController(c client.Client, informers cache.Informers, scheme *runtime.Scheme, recorder record.EventRecorder, opts Options) *SyncController {
+	var syncSlots chan struct{}
+	if opts.MaxConcurrentSyncs > 0 {
+		syncSlots = make(chan struct{}, opts.MaxConcurrentSyncs)
+	}
+	return &SyncController{Client: c, informers: informers, scheme: scheme, recorder: recorder, cron: cron.New(), opts: opts, primed: map[types.UID]struct{}{}, scheduled: map[string]scheduledEntry{}, retries: map[string]*retryState{}, remoteBackoffs: map[string]*remoteBackoff{}, syncSlots: syncSlots, running: map[string]int{}, stopping: make(chan struct{})}
+}
+
+// liveReader returns the reader for the final read of an object before it is
+// written: the API server when an apiReader is set, else the client.
+func (s *SyncController) liveReader() client.Reader {
+	if s.apiReader != nil {
+		return s.apiReader
+	}
+	return s.Client
 }
 
 func (s *SyncController) Start(ctx context.Context) error {
 	logger := log.FromContext(ctx)
 	logger.Info("starting sync scheduler")
+	s.cron.Start()
 	go s.rescheduleLoop(ctx)
 	<-ctx.Done()
 	logger.Info("stopping sync scheduler")
-	s.cron.Stop()
+	// wake jobs waiting out their jitter delay and refuse new syncs
+	s.runningMu.Lock()
+	close(s.stopping)
+	s.runningMu.Unlock()
+	s.drain(logger, s.cron.Stop().Done())
 	return nil
 }
 
 func (s *SyncController) rescheduleLoop(ctx context.Context) {
-	ticker := time.NewTicker(1 * time.Minute)
-	defer ticker.Stop()
+	s.markRescheduled(time.Now())
+	failures := 0
 	for {
+		delay := s.rescheduleInterval()
 		if err := s.buildSchedules(ctx); err != nil {
-			log.FromContext(ctx).Error(err, "failed to build schedules")
+			failures++
+			delay = rescheduleBackoff(failures, delay)
+			log.FromContext(ctx).Error(err, "failed to build schedules", "failures", failures, "retryIn", delay)
+		} else {
+			failures = 0
+			s.markSchedulesBuilt()
 		}
+		s.markRescheduled(time.Now())
+		timer := time.NewTimer(delay)
 		select {
 		case <-ctx.Done():
+			timer.Stop()
 			return
-		case <-ticker.C:
+		case <-timer.C:
 		}
 	}
 }
 
-func parseNSName(defaultNS, ref string) types.NamespacedName {
-	if strings.Contains(ref, "/") {
-		parts := strings.SplitN(ref, "/", 2)
-		return types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+// rescheduleBaseDelay is the delay before the reschedule loop retries after
+// its first failed pass.
+const rescheduleBaseDelay = 2 * time.Second
+
+// rescheduleBackoff returns the delay before the next pass of the reschedule
+// loop after the given number of consecutive failures: rescheduleBaseDelay
+// doubled per failure with up to 50% jitter, capped at interval. A transient
+// error is retried within seconds, while a persistent one falls back to the
+// normal cadence instead of retrying in a tight loop.
+func rescheduleBackoff(failures int, interval time.Duration) time.Duration {
+	d := rescheduleBaseDelay
+	for i := 1; i < failures && d < interval; i++ {
+		d *= 2
 	}
-	return types.NamespacedName{Namespace: defaultNS, Name: ref}
+	if d = wait.Jitter(d, 0.5); d > interval {
+		return interval
+	}
+	return d
+}
+
+// rescheduleInterval returns Options.RescheduleInterval, defaulting to one
+// minute.
+func (s *SyncController) rescheduleInterval() time.Duration {
+	if s.opts.RescheduleInterval <= 0 {
+		return time.Minute
+	}
+	return s.opts.RescheduleInterval
 }
 
-func (s *SyncController) buildSchedules(ctx context.Context) error {
+// parseNSName parses ref, either "name" in defaultNS or "namespace/name".
+// Surrounding whitespace is ignored; empty parts and extra slashes are
+// rejected with ErrInvalidReference.
+func parseNSName(defaultNS, ref string) (types.NamespacedName, error) {
+	ref = strings.TrimSpace(ref)
+	if ref == "" {
+		return types.NamespacedName{}, fmt.Errorf("%w: empty reference", ErrInvalidReference)
+	}
+	ns, name, found := strings.Cut(ref, "/")
+	if !found {
+		return types.NamespacedName{Namespace: defaultNS, Name: ref}, nil
+	}
+	ns, name = strings.TrimSpace(ns), strings.TrimSpace(name)
+	switch {
+	case ns == "":
+		return types.NamespacedName{}, fmt.Errorf("%w: %q has an empty namespace", ErrInvalidReference, ref)
+	case name == "":
+		return types.NamespacedName{}, fmt.Errorf("%w: %q has an empty name", ErrInvalidReference, ref)
+	case strings.Contains(name, "/"):
+		return types.NamespacedName{}, fmt.Errorf("%w: %q must be name or namespace/name", ErrInvalidReference, ref)
+	}
+	return types.NamespacedName{Namespace: ns, Name: name}, nil
+}
+
+func (s *SyncController) buildSchedules(ctx context.Context) (err error) {
+	ctx, span := startSpan(ctx, "buildSchedules")
+	defer func() { endSpan(span, err) }()
+
 	// Get current resource state
 	exportList := &unstructured.UnstructuredList{}
 	exportList.SetGroupVersionKind(schemaGVKList("CertificateExport"))
@@ -97,12 +316,23 @@ func (s *SyncController) buildSchedules(ctx context.Context) error {
 		log.FromContext(ctx).Error(err, "failed to list CertificateExports")
 		return err
 	}
-	log.FromContext(ctx).Info("found CertificateExports", "count", len(exportList.Items))
+	log.FromContext(ctx).V(1).Info("found CertificateExports", "count", len(exportList.Items))
+
+	// cluster exports are out of reach of a namespace-scoped controller
+	clusterExportList := &unstructured.UnstructuredList{}
+	clusterExportList.SetGroupVersionKind(schemaGVKList("ClusterCertificateExport"))
+	if s.opts.Namespace == "" {
+		if err := s.List(ctx, clusterExportList); err != nil {
+			log.FromContext(ctx).Error(err, "failed to list ClusterCertificateExports")
+			return err
+		}
+	}
+	log.FromContext(ctx).V(1).Info("found ClusterCertificateExports", "count", len(clusterExportList.Items))
 
 	// Debug: log export details
 	for i := range exportList.Items {
 		item := exportList.Items[i]
-		log.FromContext(ctx).Info("export details", "namespace", item.GetNamespace(), "name", item.GetName())
+		log.FromContext(ctx).V(2).Info("export details", "namespace", item.GetNamespace(), "name", item.GetName())
 	}
 
 	importList := &unstructured.UnstructuredList{}
@@ -111,212 +341,707 @@ func (s *SyncController) buildSchedules(ctx context.Context) error {
 		log.FromContext(ctx).Error(err, "failed to list CertificateImports")
 		return err
 	}
-	log.FromContext(ctx).Info("found CertificateImports", "count", len(importList.Items))
+	log.FromContext(ctx).V(1).Info("found CertificateImports", "count", len(importList.Items))
+
+	// Leave resources in namespaces outside the controller's scope alone
+	exportList.Items = s.filterNamespaces(exportList.Items)
+	importList.Items = s.filterNamespaces(importList.Items)
+
+	// List order isn't stable across calls; sort so hashing, scheduling and
+	// conflict resolution see the same order every pass
+	sortByNamespacedName(exportList.Items)
+	sortByNamespacedName(clusterExportList.Items)
+	sortByNamespacedName(importList.Items)
+
+	// Targets of imports deleted while the controller was down have no
+	// finalizer left to clean them up
+	if s.opts.GCOrphans {
+		s.collectOrphans(ctx, importList.Items, exportList.Items)
+	}
+	// Ensure finalizers and clean up imports being deleted; those are not scheduled
+	importList.Items = s.reconcileImportFinalizers(ctx, importList.Items)
+	// Likewise for push exports and their pushed secrets
+	exportList.Items = s.reconcileExportFinalizers(ctx, exportList.Items)
+	// Exports list the imports depending on them, before any are filtered out
+	s.updateImporters(ctx, append(exportList.Items, clusterExportList.Items...), importList.Items)
+	// Only one import per target secret is scheduled; the others are flagged
+	importList.Items = s.resolveTargetConflicts(ctx, importList.Items)
+	// Imports whose target feeds back into their own source are not scheduled
+	importList.Items = s.resolveCycles(ctx, importList.Items, append(exportList.Items, clusterExportList.Items...))
+	if s.opts.SyncOnSecretChange {
+		s.indexSources(importList.Items, append(exportList.Items, clusterExportList.Items...))
+	}
+	// Suspended resources keep their target but are not scheduled
+	exportList.Items = s.filterSuspended(ctx, exportList.Items)
+	importList.Items = s.filterSuspended(ctx, importList.Items)
+	// Checked on every pass, so it also catches entries that stopped firing
+	s.updateOverdue(importList.Items, time.Now())
 
 	// Debug: log import details
 	for i := range importList.Items {
 		item := importList.Items[i]
-		fromExport := getString(item.Object, "spec.fromExport")
-		log.FromContext(ctx).Info("import details", "namespace", item.GetNamespace(), "name", item.GetName(), "fromExport", fromExport)
+		log.FromContext(ctx).V(2).Info("import details", "namespace", item.GetNamespace(), "name", item.GetName(), "fromExport", getString(item.Object, "spec.fromExport"))
 	}
 
+	// Prime imports not seen before, including ones created after startup
+	s.primeImports(ctx, importList.Items)
+
 	// Check if we need to rebuild schedules (only if resources changed)
 	exportCount := len(exportList.Items)
 	importCount := len(importList.Items)
 
 	// Create a hash of all resource specs to detect content changes
-	resourceHash := s.createResourceHash(exportList.Items, importList.Items)
+	resourceHash := s.createResourceHash(append(exportList.Items, clusterExportList.Items...), importList.Items)
 
 	if exportCount == s.lastExportCount && importCount == s.lastImportCount && resourceHash == s.lastResourceHash {
 		// No changes, skip rebuild
+		scheduleRebuildsSkipped.Inc()
 		return nil
 	}
+	scheduleRebuilds.Inc()
 
 	// Update tracked state
 	s.lastExportCount = exportCount
 	s.lastImportCount = importCount
 	s.lastResourceHash = resourceHash
 
-	// Clear existing cron entries
-	for _, e := range s.cron.Entries() {
-		s.cron.Remove(e.ID)
+	// Entries still wanted after this pass; everything else is removed
+	desired := map[string]bool{}
+
+	// Schedule exports: those that push their secret to other namespaces
+	// push it, all others validate their source secret so that status
+	// reflects whether importers can use it
+	for i := range exportList.Items {
+		item := exportList.Items[i]
+		ns := item.GetNamespace()
+		name := item.GetName()
+		exp, err := toExport(&item)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "skipping export", "export", fmt.Sprintf("%s/%s", ns, name))
+			continue
+		}
+
+		schedule, err := scheduleFor(exp.Spec.Schedule, exp.Spec.Timezone)
+		var sched cron.Schedule
+		if err == nil {
+			sched, err = parseSchedule(schedule)
+		}
+		if err != nil {
+			log.FromContext(ctx).Error(err, "invalid cron schedule for export", "export", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule)
+			continue
+		}
+
+		key := scheduleKey("CertificateExport", ns, name)
+		desired[key] = true
+		if exp.Spec.TargetSecret == "" {
+			// re-added, and so validated right away, when the source changes
+			added := s.scheduleEntry(key, sched, schedule+" "+exp.Spec.SecretRef, func() {
+				s.validateExport(ns, name)
+			})
+			if added {
+				log.FromContext(ctx).V(1).Info("scheduled export validation", "export", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule)
+				s.setExportNextSyncTime(ctx, &item, sched)
+				go s.validateExport(ns, name)
+			}
+			continue
+		}
+		added := s.scheduleEntry(key, sched, schedule, func() {
+			logger := log.FromContext(context.Background())
+			logger.V(1).Info("executing export push", "export", fmt.Sprintf("%s/%s", ns, name))
+			if err := s.syncExportPush(context.Background(), ns, name); err != nil {
+				logger.Error(err, "failed to push export", "export", fmt.Sprintf("%s/%s", ns, name))
+			}
+		})
+		if added {
+			log.FromContext(ctx).Info("scheduled export push", "export", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule)
+			s.setExportNextSyncTime(ctx, &item, sched)
+		}
 	}
 
-	// Stop and restart cron to ensure clean state
-	s.cron.Stop()
-	s.cron = cron.New()
+	// Schedule cluster exports that publish a ClusterTrustBundle
+	for i := range clusterExportList.Items {
+		item := clusterExportList.Items[i]
+		if !s.opts.ClusterTrustBundles {
+			continue
+		}
+		name := item.GetName()
+		exp, err := toClusterExport(&item)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "skipping cluster export", "clusterExport", name)
+			continue
+		}
+		if exp.Spec.ClusterTrustBundle == nil || exp.Spec.ClusterTrustBundle.Name == "" {
+			continue
+		}
 
-	log.FromContext(ctx).Info("recreated cron scheduler")
+		schedule, err := scheduleFor(exp.Spec.Schedule, exp.Spec.Timezone)
+		var sched cron.Schedule
+		if err == nil {
+			sched, err = parseSchedule(schedule)
+		}
+		if err != nil {
+			log.FromContext(ctx).Error(err, "invalid cron schedule for cluster export", "clusterExport", name, "schedule", schedule)
+			continue
+		}
 
-	// CertificateExports don't need scheduling - they just define source secrets
-	// Only CertificateImports need scheduling to copy secrets
+		key := scheduleKey("ClusterCertificateExport", "", name)
+		desired[key] = true
+		added := s.scheduleEntry(key, sched, schedule, func() {
+			logger := log.FromContext(context.Background())
+			logger.V(1).Info("publishing cluster trust bundle", "clusterExport", name)
+			if err := s.syncClusterTrustBundle(context.Background(), name); err != nil {
+				logger.Error(err, "failed to publish cluster trust bundle", "clusterExport", name)
+			}
+		})
+		if added {
+			log.FromContext(ctx).Info("scheduled cluster trust bundle", "clusterExport", name, "schedule", schedule)
+		}
+	}
 
 	// Schedule imports
 	for i := range importList.Items {
 		item := importList.Items[i]
-		schedule := getString(item.Object, "spec.schedule")
-		if schedule == "" {
-			schedule = "@every 1h"
-		}
-		fromExport := getString(item.Object, "spec.fromExport")
-		targetSecret := getString(item.Object, "spec.targetSecret")
 		ns := item.GetNamespace()
 		name := item.GetName()
-
-		// Validate cron expression - standard 5-field format only
-		var parser cron.Parser
-		if strings.HasPrefix(schedule, "@") {
-			// @every, @daily, etc. - use descriptor parser
-			parser = cron.NewParser(cron.Descriptor)
-		} else {
-			// Standard 5-field cron format: minute hour day month day-of-week
-			parser = cron.NewParser(cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+		imp, err := toImport(&item)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "skipping import", "import", fmt.Sprintf("%s/%s", ns, name))
+			continue
 		}
 
-		if _, err := parser.Parse(schedule); err != nil {
+		schedule, err := scheduleFor(imp.Spec.Schedule, imp.Spec.Timezone)
+		var sched cron.Schedule
+		if err == nil {
+			sched, err = parseSchedule(schedule)
+		}
+		if err != nil {
 			log.FromContext(ctx).Error(err, "invalid cron schedule for import", "import", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule)
 			continue
 		}
+		jitter, err := s.importJitter(imp)
+		if err != nil {
+			log.FromContext(ctx).Error(err, "invalid jitter for import", "import", fmt.Sprintf("%s/%s", ns, name))
+			continue
+		}
+		delay := jitterDelay(string(imp.UID), jitter)
 
-		log.FromContext(ctx).Info("scheduling import", "import", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule)
-		entryID, err := s.cron.AddFunc(schedule, func() {
+		key := scheduleKey("CertificateImport", ns, name)
+		desired[key] = true
+		added := s.scheduleEntry(key, sched, fmt.Sprintf("%s|%s", schedule, delay), func() {
 			logger := log.FromContext(context.Background())
-			logger.Info("executing import sync", "import", fmt.Sprintf("%s/%s", ns, name))
-			if err := s.syncImport(context.Background(), ns, name, fromExport, targetSecret); err != nil {
-				logger.Error(err, "failed to sync import", "import", fmt.Sprintf("%s/%s", ns, name))
+			if delay > 0 {
+				select {
+				case <-time.After(delay):
+				case <-s.stopping:
+					return
+				}
+			}
+			logger.V(1).Info("executing import sync", "import", fmt.Sprintf("%s/%s", ns, name))
+			if err := s.runImportSync(context.Background(), ns, name); err != nil {
+				if !errors.Is(err, errSyncInProgress) {
+					logger.Error(err, "failed to sync import", "import", fmt.Sprintf("%s/%s", ns, name))
+				}
 			} else {
 				// Log completion and next run time
-				logger.Info("import sync completed", "import", fmt.Sprintf("%s/%s", ns, name))
+				logger.V(1).Info("import sync completed", "import", fmt.Sprintf("%s/%s", ns, name))
 				// Get all entries to find the next run time for this import
 				for _, entry := range s.cron.Entries() {
 					if entry.Valid() {
-						logger.Info("next scheduled run", "import", fmt.Sprintf("%s/%s", ns, name), "nextRun", entry.Next)
+						logger.V(2).Info("next scheduled run", "import", fmt.Sprintf("%s/%s", ns, name), "nextRun", entry.Next)
 						break // Only log the first valid entry's next run
 					}
 				}
 			}
 		})
-		if err != nil {
-			log.FromContext(ctx).Error(err, "failed to schedule import", "import", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule)
-		} else {
-			log.FromContext(ctx).Info("import scheduled successfully", "import", fmt.Sprintf("%s/%s", ns, name), "entryID", entryID)
+		if added {
+			log.FromContext(ctx).Info("scheduled import", "import", fmt.Sprintf("%s/%s", ns, name), "schedule", schedule, "jitterDelay", delay)
+			next := sched.Next(time.Now()).Add(delay)
+			_ = s.updateStatus(ctx, &item, func(imp *unstructured.Unstructured) bool {
+				return setNextSyncTime(imp, next)
+			})
+		}
+	}
+
+	for key, entry := range s.scheduled {
+		if !desired[key] {
+			s.cron.Remove(entry.id)
+			delete(s.scheduled, key)
+			log.FromContext(ctx).Info("unscheduled", "entry", key)
+		}
+	}
+
+	cronEntries.Set(float64(len(s.scheduled)))
+	log.FromContext(ctx).V(1).Info("schedules updated", "entries", len(s.scheduled))
+
+	return nil
+}
+
+// scheduledEntry is a cron entry along with the hash of the inputs it was
+// built from.
+type scheduledEntry struct {
+	id   cron.EntryID
+	hash string
+}
+
+func scheduleKey(kind, ns, name string) string {
+	return fmt.Sprintf("%s %s/%s", kind, ns, name)
+}
+
+// scheduleEntry adds the cron entry for key, replacing the existing one if
+// its hash differs. An entry with an unchanged hash is left alone so its
+// next run time is kept. It reports whether an entry was added.
+func (s *SyncController) scheduleEntry(key string, sched cron.Schedule, hash string, job func()) bool {
+	if cur, ok := s.scheduled[key]; ok {
+		if cur.hash == hash {
+			return false
 		}
+		s.cron.Remove(cur.id)
+		delete(s.scheduled, key)
 	}
+	id := s.cron.Schedule(sched, cron.FuncJob(job))
+	s.scheduled[key] = scheduledEntry{id: id, hash: hash}
+	return true
+}
 
-	// Start cron if not already running
-	if len(s.cron.Entries()) > 0 {
-		s.cron.Start()
-		log.FromContext(ctx).Info("cron scheduler started", "entries", len(s.cron.Entries()))
+// primeImports triggers an immediate sync for every import seen for the first
+// time that syncs on start, and forgets imports that no longer exist.
+// spec.syncOnStart decides per import, defaulting to Options.ImmediateOnStart.
+func (s *SyncController) primeImports(ctx context.Context, items []unstructured.Unstructured) {
+	seen := make(map[types.UID]struct{}, len(items))
+	var pending []types.NamespacedName
+	for i := range items {
+		uid := items[i].GetUID()
+		seen[uid] = struct{}{}
+		if _, ok := s.primed[uid]; ok {
+			continue
+		}
+		s.primed[uid] = struct{}{}
+		if !getBool(items[i].Object, "spec.syncOnStart", s.opts.ImmediateOnStart) {
+			continue
+		}
+		pending = append(pending, types.NamespacedName{Namespace: items[i].GetNamespace(), Name: items[i].GetName()})
+	}
+	for uid := range s.primed {
+		if _, ok := seen[uid]; !ok {
+			delete(s.primed, uid)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
 
-		// Debug: log next run times for all entries
-		for _, entry := range s.cron.Entries() {
-			log.FromContext(ctx).Info("cron entry details", "entryID", entry.ID, "nextRun", entry.Next, "valid", entry.Valid())
+	log.FromContext(ctx).Info("triggering immediate import sync", "count", len(pending))
+	pendingSyncs.Add(float64(len(pending)))
+	go func() {
+		// Prime only once informers are synced so syncs don't read stale state
+		if s.informers != nil && !s.informers.WaitForCacheSync(ctx) {
+			log.FromContext(ctx).Info("cache did not sync, skipping immediate import sync")
+			pendingSyncs.Sub(float64(len(pending)))
+			return
 		}
+		for _, key := range pending {
+			pendingSyncs.Dec()
+			log.FromContext(context.Background()).Info("triggering immediate import sync", "import", key.String())
+			if err := s.runImportSync(context.Background(), key.Namespace, key.Name); err != nil && !errors.Is(err, errSyncInProgress) {
+				log.FromContext(context.Background()).Error(err, "failed to sync import", "import", key.String())
+			}
+		}
+	}()
+}
 
-		// Test job removed - cron is working correctly
-	} else {
-		log.FromContext(ctx).Info("cron scheduler has no entries to start")
-	}
-
-	// Optionally trigger a one-time immediate sync on start to prime state.
-	if s.immediateOnStart && !s.immediateOnce {
-		if len(importList.Items) > 0 {
-			s.immediateOnce = true
-			log.FromContext(ctx).Info("triggering immediate import sync on start")
-			go func() {
-				time.Sleep(5 * time.Second) // Wait a bit for cron to start
-				for i := range importList.Items {
-					item := importList.Items[i]
-					fromExport := getString(item.Object, "spec.fromExport")
-					targetSecret := getString(item.Object, "spec.targetSecret")
-					ns := item.GetNamespace()
-					name := item.GetName()
-					log.FromContext(context.Background()).Info("triggering immediate import sync", "import", fmt.Sprintf("%s/%s", ns, name))
-					if err := s.syncImport(context.Background(), ns, name, fromExport, targetSecret); err != nil {
-						log.FromContext(context.Background()).Error(err, "failed to sync import", "import", fmt.Sprintf("%s/%s", ns, name))
-					}
-				}
-			}()
+// importJitter returns spec.jitter of an import, falling back to the global
+// Options.SyncJitter when unset.
+func (s *SyncController) importJitter(imp *certtrustv1.CertificateImport) (time.Duration, error) {
+	if imp.Spec.Jitter == nil {
+		return s.opts.SyncJitter, nil
+	}
+	d := imp.Spec.Jitter.Duration
+	if d < 0 {
+		return 0, fmt.Errorf("invalid spec.jitter %q: must not be negative", d)
+	}
+	return d, nil
+}
+
+// jitterDelay derives a delay in [0, jitter) from uid. It is stable for a
+// given import across rebuilds but spreads different imports apart.
+func jitterDelay(uid string, jitter time.Duration) time.Duration {
+	if jitter <= 0 {
+		return 0
+	}
+	h := fnv.New64a()
+	h.Write([]byte(uid))
+	return time.Duration(h.Sum64() % uint64(jitter))
+}
+
+// DefaultSchedule is used when spec.schedule is empty, unless
+// SetDefaultSchedule overrides it.
+const DefaultSchedule = "@every 1h"
+
+// defaultSchedule is the schedule of imports and exports without
+// spec.schedule.
+var defaultSchedule = DefaultSchedule
+
+// defaultFromExport is the export of imports that write a secret without
+// setting spec.fromExport, set by SetDefaultFromExport. Empty disables it.
+var defaultFromExport string
+
+// fromExportOf returns the export an import copies its secret from:
+// spec.fromExport, or defaultFromExport for an import with spec.targetSecret
+// that does not set it. Configmap imports never use the default.
+func fromExportOf(imp *unstructured.Unstructured) string {
+	if ref := getString(imp.Object, "spec.fromExport"); ref != "" || getString(imp.Object, "spec.targetConfigMap") != "" {
+		return ref
+	}
+	return defaultFromExport
+}
+
+// scheduleSpec returns the schedule of an import or export, anchored to
+// spec.timezone when set. The timezone must be a known IANA zone and can't
+// be combined with a CRON_TZ= or TZ= prefix in spec.schedule.
+func scheduleSpec(obj *unstructured.Unstructured) (string, error) {
+	return scheduleFor(getString(obj.Object, "spec.schedule"), getString(obj.Object, "spec.timezone"))
+}
+
+// scheduleFor is scheduleSpec for a spec.schedule and spec.timezone already
+// read from a typed object.
+func scheduleFor(schedule, tz string) (string, error) {
+	if schedule == "" {
+		schedule = defaultSchedule
+	}
+	if tz == "" {
+		return schedule, nil
+	}
+	if _, err := time.LoadLocation(tz); err != nil {
+		return "", fmt.Errorf("unknown spec.timezone %q: %v", tz, err)
+	}
+	if strings.HasPrefix(schedule, "CRON_TZ=") || strings.HasPrefix(schedule, "TZ=") {
+		return "", fmt.Errorf("spec.timezone %q conflicts with the timezone prefix of spec.schedule %q", tz, schedule)
+	}
+	return fmt.Sprintf("CRON_TZ=%s %s", tz, schedule), nil
+}
+
+// parseSchedule parses a schedule the way it is scheduled: @-descriptors
+// (@every, @daily, etc.), the standard 5-field cron format, or a 6-field
+// format with a leading seconds field, optionally prefixed with
+// CRON_TZ=<zone> to evaluate it in that timezone.
+func parseSchedule(schedule string) (cron.Schedule, error) {
+	fields := strings.Fields(schedule)
+	if len(fields) > 0 && (strings.HasPrefix(fields[0], "CRON_TZ=") || strings.HasPrefix(fields[0], "TZ=")) {
+		fields = fields[1:]
+	}
+	opts := cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow | cron.Descriptor
+	if len(fields) > 0 && !strings.HasPrefix(fields[0], "@") {
+		switch len(fields) {
+		case 5:
+		case 6:
+			// second minute hour day month day-of-week
+			opts |= cron.Second
+		default:
+			return nil, fmt.Errorf("expected 5 fields (minute hour day month weekday) or 6 with leading seconds, got %d", len(fields))
 		}
 	}
+	return cron.NewParser(opts).Parse(schedule)
+}
 
-	return nil
+// setExportNextSyncTime records in the status of a newly scheduled export
+// when sched next fires (best-effort).
+func (s *SyncController) setExportNextSyncTime(ctx context.Context, exp *unstructured.Unstructured, sched cron.Schedule) {
+	next := sched.Next(time.Now())
+	_ = s.updateStatus(ctx, exp, func(exp *unstructured.Unstructured) bool {
+		return setNextSyncTime(exp, next)
+	})
+}
+
+// validateExport runs syncExport for a scheduled export, logging failures.
+func (s *SyncController) validateExport(namespace, name string) {
+	logger := log.FromContext(context.Background())
+	logger.V(1).Info("validating export", "export", fmt.Sprintf("%s/%s", namespace, name))
+	if err := s.syncExport(context.Background(), namespace, name); err != nil && !errors.Is(err, errShuttingDown) {
+		logger.Error(err, "export source is invalid", "export", fmt.Sprintf("%s/%s", namespace, name))
+	}
 }
 
-func (s *SyncController) syncExport(ctx context.Context, namespace, name, secretRef string) error {
+// syncExport checks that the source secret of a CertificateExport exists and
+// has an accepted type, and reports the result in the SourceValid condition.
+func (s *SyncController) syncExport(ctx context.Context, namespace, name string) (err error) {
+	ctx, span := startSpan(ctx, "syncExport", attribute.String("export.namespace", namespace), attribute.String("export.name", name))
+	defer func() { endSpan(span, err) }()
 	logger := log.FromContext(ctx).WithValues("export", fmt.Sprintf("%s/%s", namespace, name))
+	release, err := s.acquireSync(ctx, scheduleKey("CertificateExport", namespace, name))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schemaGVK("CertificateExport"))
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		logger.Error(err, "failed to get export")
+		return err
+	}
+	if isSuspended(obj) {
+		logger.V(1).Info("export is suspended, skipping sync")
+		return nil
+	}
+	secretRef := getString(obj.Object, "spec.secretRef")
+	span.SetAttributes(attribute.String("source.secret", secretRef))
+	defer func() {
+		// only transitions of the condition are worth an event, the export
+		// is validated on every scheduled run
+		changed := false
+		_ = s.updateStatus(ctx, obj, func(obj *unstructured.Unstructured) bool {
+			if err != nil {
+				changed = setCondition(obj, conditionSourceValid, metav1.ConditionFalse, eventReasonFor(err), err.Error())
+			} else {
+				changed = setCondition(obj, conditionSourceValid, metav1.ConditionTrue, reasonSourceFound, fmt.Sprintf("source secret %s is valid", secretRef))
+			}
+			return changed
+		})
+		if changed {
+			s.recordSyncResult(obj, err, fmt.Sprintf("source secret %s is valid", secretRef))
+		}
+	}()
 
 	// Verify the source secret exists and is valid
 	var src corev1.Secret
-	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: secretRef}, &src); err != nil {
-		logger.Error(err, "failed to get source secret")
+	if err := s.getSourceSecret(ctx, types.NamespacedName{Namespace: namespace, Name: secretRef}, &src); err != nil {
 		return err
 	}
 
-	if src.Type != corev1.SecretTypeTLS {
-		logger.Error(fmt.Errorf("invalid secret type"), "source secret must be type kubernetes.io/tls", "type", src.Type)
-		return fmt.Errorf("source secret %s/%s must be type kubernetes.io/tls", src.Namespace, src.Name)
+	if err := checkSourceType(obj, &src); err != nil {
+		return err
 	}
 
-	logger.Info("export sync completed", "secretRef", secretRef, "secretType", src.Type)
+	logger.V(1).Info("export sync completed", "secretRef", secretRef, "secretType", src.Type)
 
-	// Update status.lastSyncTime on the export (best-effort)
-	obj := &unstructured.Unstructured{}
-	obj.SetGroupVersionKind(schemaGVK("CertificateExport"))
-	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err == nil {
-		setString(obj.Object, "status.lastSyncTime", time.Now().UTC().Format(time.RFC3339))
-		_ = s.Status().Update(ctx, obj)
-	}
+	// Record the sync in the status of the export (best-effort)
+	_ = s.updateStatus(ctx, obj, func(obj *unstructured.Unstructured) bool {
+		markSynced(obj)
+		setCertificateStatus(obj, src.Data)
+		if next, err := s.nextSyncTime(obj, time.Now()); err == nil {
+			setNextSyncTime(obj, next)
+		}
+		return true
+	})
 
 	return nil
 }
 
-func (s *SyncController) syncImport(ctx context.Context, namespace, name, fromExport, targetSecret string) error {
+func (s *SyncController) syncImport(ctx context.Context, namespace, name string) (err error) {
+	ctx, span := startSpan(ctx, "syncImport", attribute.String("import.namespace", namespace), attribute.String("import.name", name))
+	defer func() { endSpan(span, err) }()
 	logger := log.FromContext(ctx).WithValues("import", fmt.Sprintf("%s/%s", namespace, name))
+	release, err := s.acquireSync(ctx, scheduleKey("CertificateImport", namespace, name))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, imp); err != nil {
+		logger.Error(err, "failed to get import")
+		return err
+	}
+	if !imp.GetDeletionTimestamp().IsZero() {
+		// being deleted; the finalizer cleans up the target
+		return nil
+	}
+	if isSuspended(imp) {
+		logger.Info("import is suspended, skipping sync")
+		return nil
+	}
+	defer func() { s.recordSyncResult(imp, err, fmt.Sprintf("synced %s", importTarget(imp))) }()
+	if err := s.checkNamespaceAllowed(namespace); err != nil {
+		logger.Error(err, "refusing to write target")
+		return err
+	}
+	typed, err := toImport(imp)
+	if err != nil {
+		logger.Error(err, "failed to read import spec")
+		return err
+	}
+	spec := &typed.Spec
+	if err := s.checkExportRefs(ctx, imp); err != nil {
+		logger.Error(err, "invalid export reference")
+		return err
+	}
+	if err := s.checkTargetName(ctx, imp); err != nil {
+		logger.Error(err, "invalid target name")
+		return err
+	}
+	if spec.TargetConfigMap != "" {
+		return s.syncBundleImport(ctx, imp)
+	}
+	fromExport := fromExportOf(imp)
+	targetSecret := spec.TargetSecret
 
 	// Debug: log the fromExport reference being parsed
-	logger.Info("parsing export reference", "fromExport", fromExport, "importNamespace", namespace)
+	logger.V(2).Info("parsing export reference", "fromExport", fromExport, "importNamespace", namespace)
 
 	// resolve export
-	expKey := parseNSName(namespace, fromExport)
-	logger.Info("resolved export key", "exportNamespace", expKey.Namespace, "exportName", expKey.Name)
-	exp := &unstructured.Unstructured{}
-	exp.SetGroupVersionKind(schemaGVK("CertificateExport"))
-	if err := s.Get(ctx, expKey, exp); err != nil {
+	expKind, expKey, err := exportKind(namespace, fromExport)
+	if err != nil {
+		return err
+	}
+	logger.V(2).Info("resolved export key", "exportKind", expKind, "exportNamespace", expKey.Namespace, "exportName", expKey.Name)
+	exp, err := getExport(ctx, s, namespace, fromExport)
+	if err != nil {
 		logger.Error(err, "failed to get export")
 		return err
 	}
-	secretRef := getString(exp.Object, "spec.secretRef")
+	if err := s.authorizeImport(ctx, imp, exp); err != nil {
+		logger.Error(err, "refusing to copy from export")
+		return err
+	}
+	srcKey := exportSource(exp)
+	secretRef := srcKey.Name
+	span.SetAttributes(attribute.String("export.kind", expKind), attribute.String("source.secret", srcKey.String()))
+	if tgtKey := (types.NamespacedName{Namespace: namespace, Name: targetSecret}); srcKey == tgtKey || inCycle(imp) {
+		err := fmt.Errorf("%w: import %s/%s: target secret %s would feed back into its own source", ErrCyclicReference, namespace, name, tgtKey)
+		logger.Error(err, "refusing to write target secret")
+		if srcKey == tgtKey {
+			_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+				return setCondition(imp, conditionCyclicReference, metav1.ConditionTrue, reasonSelfReference, err.Error())
+			})
+		}
+		return err
+	}
 	// read source secret
 	var src corev1.Secret
-	if err := s.Get(ctx, types.NamespacedName{Namespace: exp.GetNamespace(), Name: secretRef}, &src); err != nil {
-		logger.Error(err, "failed to get source secret", "secretRef", secretRef, "namespace", exp.GetNamespace())
+	if err := s.getSourceSecret(ctx, srcKey, &src); err != nil {
+		logger.Error(err, "failed to get source secret", "secretRef", secretRef, "namespace", srcKey.Namespace)
+		return err
+	}
+	if err := checkSourceType(exp, &src); err != nil {
 		return err
 	}
-	if src.Type != corev1.SecretTypeTLS {
-		return fmt.Errorf("source secret %s/%s must be type kubernetes.io/tls", src.Namespace, src.Name)
+
+	// hold back a placeholder source until whatever materializes it has
+	// filled in its data
+	if spec.WaitForSourceData {
+		empty := checkSourceData(src.Type, src.Data)
+		_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+			if empty == nil {
+				return removeCondition(imp, conditionSourceEmpty)
+			}
+			return setCondition(imp, conditionSourceEmpty, metav1.ConditionTrue, reasonNotPopulated, empty.Error())
+		})
+		if empty != nil {
+			err := fmt.Errorf("source secret %s: %w", srcKey, empty)
+			logger.Info("source secret is not populated yet, skipping sync", "reason", empty.Error())
+			return err
+		}
+	}
+
+	// hold back a source that is mid-rotation instead of mirroring it; pull
+	// secrets carry no certificate to check
+	if spec.WaitForValidSource && src.Type != corev1.SecretTypeDockerConfigJson {
+		notReady := checkSourceReady(src.Type, src.Data, time.Now())
+		_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+			if notReady == nil {
+				return removeCondition(imp, conditionSourceNotReady)
+			}
+			return setCondition(imp, conditionSourceNotReady, metav1.ConditionTrue, reasonInvalidSource, notReady.Error())
+		})
+		if notReady != nil {
+			err := fmt.Errorf("source secret %s: %w", srcKey, notReady)
+			logger.Info("source not ready, skipping sync", "reason", notReady.Error())
+			return err
+		}
+	}
+
+	// refuse a source whose leaf is not the pinned one
+	if spec.ExpectedFingerprint != "" {
+		want := normalizeFingerprint(spec.ExpectedFingerprint)
+		got, err := leafFingerprint(src.Data[corev1.TLSCertKey])
+		var mismatch error
+		switch {
+		case err != nil:
+			mismatch = fmt.Errorf("%w: source secret %s: tls.crt does not parse: %v", ErrFingerprintMismatch, srcKey, err)
+		case got != want:
+			mismatch = fmt.Errorf("%w: source secret %s: leaf certificate has SHA-256 fingerprint %s, expected %s", ErrFingerprintMismatch, srcKey, got, want)
+		}
+		_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+			if mismatch == nil {
+				return removeCondition(imp, conditionFingerprintMismatch)
+			}
+			return setCondition(imp, conditionFingerprintMismatch, metav1.ConditionTrue, reasonUnexpectedCertificate, mismatch.Error())
+		})
+		if mismatch != nil {
+			logger.Error(mismatch, "refusing to mirror unexpected certificate")
+			return mismatch
+		}
+	}
+
+	// merge the ca.crt of every export in fromExports into the primary's;
+	// tls.crt and tls.key only ever come from the primary
+	srcData, err := s.importSourceData(ctx, imp, spec, &src)
+	if err != nil {
+		logger.Error(err, "failed to merge CA certificates")
+		return err
 	}
 
 	// Debug: log source secret info
-	logger.Info("source secret found", "secretRef", secretRef, "type", src.Type, "hasTlsCrt", src.Data["tls.crt"] != nil, "hasTlsKey", src.Data["tls.key"] != nil, "hasCaCrt", src.Data["ca.crt"] != nil)
+	logger.V(2).Info("source secret found", "secretRef", secretRef, "type", src.Type, "hasTlsCrt", src.Data["tls.crt"] != nil, "hasTlsKey", src.Data["tls.key"] != nil, "hasCaCrt", src.Data["ca.crt"] != nil)
+
+	// plan the target; an invalid key pair is never distributed
+	planSrc := &corev1.Secret{ObjectMeta: src.ObjectMeta, Type: src.Type, Data: srcData}
+	selected, tgtData, tgtType, err := planImport(planSrc, spec, namespace, name)
+	if errors.Is(err, ErrInvalidCertificate) {
+		logger.Error(err, "refusing to copy invalid key pair")
+		_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+			return setCondition(imp, conditionInvalidCertificate, metav1.ConditionTrue, reasonInvalidKeyPair, err.Error())
+		})
+		return err
+	}
+	if err != nil {
+		logger.Error(err, "failed to plan target data")
+		return err
+	}
+
 	// upsert target secret
 	var tgt corev1.Secret
 	tgtKey := types.NamespacedName{Namespace: namespace, Name: targetSecret}
-	if err := s.Get(ctx, tgtKey, &tgt); err != nil {
+	getErr := s.liveReader().Get(ctx, tgtKey, &tgt)
+	var current *corev1.Secret
+	if getErr == nil {
+		current = &tgt
+	}
+	keystoreChecksums, err := s.addKeystores(ctx, imp, selected, tgtData, current)
+	if err != nil {
+		logger.Error(err, "failed to build keystore")
+		return fmt.Errorf("import %s/%s: %w", namespace, name, err)
+	}
+	// a write the API server would reject for its size is not attempted
+	if size := dataSize(tgtData); s.opts.MaxSecretSize > 0 && size > s.opts.MaxSecretSize {
+		err := fmt.Errorf("%w: target secret %s would hold %d bytes of data, more than the limit of %d (--max-secret-size)", ErrSecretTooLarge, tgtKey, size, s.opts.MaxSecretSize)
+		logger.Error(err, "refusing to write target secret")
+		_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+			return setCondition(imp, conditionSecretTooLarge, metav1.ConditionTrue, reasonSizeLimitExceeded, err.Error())
+		})
+		return err
+	}
+	if getErr != nil {
 		// Secret doesn't exist, create it
-		tgtData := map[string][]byte{
-			"tls.crt": src.Data["tls.crt"],
-			"tls.key": src.Data["tls.key"],
-		}
-		// Copy ca.crt if it exists in the source secret
-		if src.Data["ca.crt"] != nil {
-			tgtData["ca.crt"] = src.Data["ca.crt"]
-		}
 		tgt = corev1.Secret{
 			ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: targetSecret},
-			Type:       corev1.SecretTypeTLS,
+			Type:       tgtType,
 			Data:       tgtData,
 		}
+		if spec.ImmutableTarget {
+			immutable := true
+			tgt.Immutable = &immutable
+		}
+		applyTargetMetadata(&tgt.ObjectMeta, imp)
+		setSourceAnnotations(tgt.Annotations, expKind, expKey, srcKey)
+		setKeystoreAnnotations(tgt.Annotations, keystoreChecksums)
+		tgt.Annotations[managedKeysAnnotation] = joinKeys(tgtData)
+		tgt.Annotations[checksumAnnotation] = dataChecksum(tgt.Data)
+		if err := controllerutil.SetControllerReference(imp, &tgt, s.scheme); err != nil {
+			logger.Error(err, "failed to set owner reference on target secret", "targetSecret", targetSecret)
+			return err
+		}
 		if err := s.Create(ctx, &tgt); err != nil {
 			logger.Error(err, "failed to create target secret", "targetSecret", targetSecret, "namespace", namespace)
 			return err
@@ -324,35 +1049,347 @@ func (s *SyncController) syncImport(ctx context.Context, namespace, name, fromEx
 		logger.Info("created target secret", "targetSecret", targetSecret, "namespace", namespace)
 	} else {
 		// Secret exists, update it
+		orig := tgt.DeepCopy()
 		if tgt.Data == nil {
 			tgt.Data = map[string][]byte{}
 		}
-		tgt.Type = corev1.SecretTypeTLS
-		tgt.Data["tls.crt"] = src.Data["tls.crt"]
-		tgt.Data["tls.key"] = src.Data["tls.key"]
-		// Copy ca.crt if it exists in the source secret
-		if src.Data["ca.crt"] != nil {
-			tgt.Data["ca.crt"] = src.Data["ca.crt"]
+		tgt.Type = tgtType
+		// Remove keys written by a previous sync that are no longer selected or
+		// no longer in the source. Secrets written before the keys were
+		// tracked fall back to the well-known keys.
+		if prev, ok := tgt.Annotations[managedKeysAnnotation]; ok {
+			for _, k := range strings.Split(prev, ",") {
+				if _, ok := tgtData[k]; !ok {
+					delete(tgt.Data, k)
+				}
+			}
 		} else {
-			// Remove ca.crt if it doesn't exist in source
-			delete(tgt.Data, "ca.crt")
+			for _, k := range tlsKeys {
+				if _, ok := tgtData[k]; !ok {
+					delete(tgt.Data, k)
+				}
+			}
+			for k := range tgt.Data {
+				if _, ok := tgtData[k]; !ok && splitCAKey.MatchString(k) {
+					delete(tgt.Data, k)
+				}
+			}
+		}
+		for k, v := range tgtData {
+			tgt.Data[k] = v
 		}
-		if err := s.Update(ctx, &tgt); err != nil {
-			logger.Error(err, "failed to update target secret", "targetSecret", targetSecret, "namespace", namespace)
+		// Refuse to overwrite a secret that this import does not manage,
+		// unless it is unmanaged and the import opts in to adopting it.
+		owner := fmt.Sprintf("%s/%s", namespace, name)
+		adopt := imp.GetAnnotations()[adoptAnnotation] == "true"
+		ownedByImport := metav1.IsControlledBy(&tgt, imp)
+		if managedBy := tgt.Annotations[managedByAnnotation]; managedBy != owner && !ownedByImport && (managedBy != "" || !adopt) {
+			err := fmt.Errorf("%w: target secret %s/%s is not managed by import %s (managed-by: %q)", ErrTargetNotManaged, namespace, targetSecret, owner, managedBy)
+			logger.Error(err, "refusing to overwrite target secret")
+			_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+				return setCondition(imp, conditionConflict, metav1.ConditionTrue, reasonTargetNotManaged, err.Error())
+			})
 			return err
 		}
-		logger.Info("updated target secret", "targetSecret", targetSecret, "namespace", namespace)
-	}
-	// Update status.lastSyncTime on the import (best-effort)
-	obj := &unstructured.Unstructured{}
-	obj.SetGroupVersionKind(schemaGVK("CertificateImport"))
-	if err := s.Get(ctx, types.NamespacedName{Namespace: namespace, Name: name}, obj); err == nil {
-		setString(obj.Object, "status.lastSyncTime", time.Now().UTC().Format(time.RFC3339))
-		_ = s.Status().Update(ctx, obj)
+		applyTargetMetadata(&tgt.ObjectMeta, imp)
+		// Only take ownership of an existing secret when it has no controller
+		// owner and the import explicitly opts in to adoption.
+		if metav1.GetControllerOf(&tgt) == nil && adopt {
+			if err := controllerutil.SetControllerReference(imp, &tgt, s.scheme); err != nil {
+				logger.Error(err, "failed to adopt target secret", "targetSecret", targetSecret)
+				return err
+			}
+			logger.Info("adopting target secret", "targetSecret", targetSecret, "namespace", namespace)
+		}
+		setSourceAnnotations(tgt.Annotations, expKind, expKey, srcKey)
+		setKeystoreAnnotations(tgt.Annotations, keystoreChecksums)
+		tgt.Annotations[managedKeysAnnotation] = joinKeys(tgtData)
+		tgt.Annotations[checksumAnnotation] = dataChecksum(tgt.Data)
+		if spec.ImmutableTarget {
+			immutable := true
+			tgt.Immutable = &immutable
+		} else if isImmutable(orig) {
+			tgt.Immutable = nil
+		}
+		// Skip the write when the checksum and metadata are unchanged. The stored
+		// checksum must also match the stored data, so external edits are still
+		// overwritten.
+		if unchanged := orig.Annotations[checksumAnnotation] == dataChecksum(orig.Data) &&
+			orig.Type == tgt.Type && isImmutable(orig) == isImmutable(&tgt) &&
+			equality.Semantic.DeepEqual(orig.ObjectMeta, tgt.ObjectMeta); unchanged {
+			logger.V(1).Info("target secret up to date, skipping update", "targetSecret", targetSecret, "namespace", namespace)
+		} else if orig.Type != tgt.Type || isImmutable(orig) && (dataChecksum(orig.Data) != dataChecksum(tgt.Data) || !isImmutable(&tgt)) {
+			// the type of a secret, and the data of an immutable one, can
+			// only change by replacing it
+			if err := s.recreateSecret(ctx, orig, &tgt); err != nil {
+				logger.Error(err, "failed to recreate target secret", "targetSecret", targetSecret, "namespace", namespace)
+				return err
+			}
+			logger.Info("recreated target secret", "targetSecret", targetSecret, "namespace", namespace, "type", tgt.Type)
+			if len(spec.RolloutTargets) > 0 && dataChecksum(orig.Data) != dataChecksum(tgt.Data) {
+				s.restartWorkloads(ctx, imp, spec.RolloutTargets)
+			}
+		} else {
+			if err := s.Update(ctx, &tgt); err != nil {
+				logger.Error(err, "failed to update target secret", "targetSecret", targetSecret, "namespace", namespace)
+				return err
+			}
+			logger.Info("updated target secret", "targetSecret", targetSecret, "namespace", namespace)
+			// only a change of content warrants restarting the workloads
+			// that mount the secret, not one of its metadata
+			if len(spec.RolloutTargets) > 0 && dataChecksum(orig.Data) != dataChecksum(tgt.Data) {
+				s.restartWorkloads(ctx, imp, spec.RolloutTargets)
+			}
+		}
 	}
+	// Record the sync in the status of the import (best-effort)
+	_ = s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		markSynced(imp)
+		removeConditionWithReason(imp, conditionConflict, reasonTargetNotManaged)
+		removeCondition(imp, conditionInvalidCertificate)
+		removeCondition(imp, conditionNotAuthorized)
+		removeCondition(imp, conditionSecretTooLarge)
+		unstructured.RemoveNestedField(imp.Object, "status", "retryBackoff")
+		setCertificateStatus(imp, selected)
+		s.checkExpiry(imp, selected)
+		if next, err := s.nextSyncTime(imp, time.Now()); err == nil {
+			setNextSyncTime(imp, next)
+		}
+		return true
+	})
 	return nil
 }
 
+// importSourceData returns the data of the source secret src of a secret
+// import, with the ca.crt of every export in spec.fromExports merged into
+// its own. src itself is not modified.
+func (s *SyncController) importSourceData(ctx context.Context, imp *unstructured.Unstructured, spec *certtrustv1.CertificateImportSpec, src *corev1.Secret) (map[string][]byte, error) {
+	if len(spec.FromExports) == 0 {
+		return src.Data, nil
+	}
+	cas, srcKeys, err := s.collectExportCAs(ctx, imp, spec.FromExports)
+	if err != nil {
+		return nil, err
+	}
+	trace.SpanFromContext(ctx).SetAttributes(attribute.StringSlice("source.secrets", srcKeys))
+	if ca := src.Data["ca.crt"]; len(ca) > 0 {
+		cas = append([][]byte{ca}, cas...)
+	}
+	merged, count, err := buildCABundle(cas...)
+	if err != nil {
+		return nil, fmt.Errorf("import %s/%s: %w", imp.GetNamespace(), imp.GetName(), err)
+	}
+	data := make(map[string][]byte, len(src.Data)+1)
+	for k, v := range src.Data {
+		data[k] = v
+	}
+	data["ca.crt"] = merged
+	log.FromContext(ctx).V(1).Info("merged CA certificates", "exports", len(spec.FromExports)+1, "certificates", count)
+	return data, nil
+}
+
+// isImmutable reports whether secret is marked immutable.
+func isImmutable(secret *corev1.Secret) bool {
+	return secret.Immutable != nil && *secret.Immutable
+}
+
+// recreateSecret replaces the secret current with desired, the only way to
+// change its type, or the data of an immutable secret. The delete only
+// applies to the version that was read, so a concurrent replacement is not
+// lost, and the create follows right away to keep the time the secret is
+// missing short. Should the create fail, the next sync retry creates the
+// secret from scratch.
+func (s *SyncController) recreateSecret(ctx context.Context, current, desired *corev1.Secret) error {
+	uid, rv := current.UID, current.ResourceVersion
+	if err := s.Delete(ctx, current, client.Preconditions{UID: &uid, ResourceVersion: &rv}); err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+	desired.UID = ""
+	desired.ResourceVersion = ""
+	desired.CreationTimestamp = metav1.Time{}
+	desired.DeletionTimestamp = nil
+	desired.ManagedFields = nil
+	return s.Create(ctx, desired)
+}
+
+// joinKeys returns the sorted keys of data as a comma-separated list.
+func joinKeys(data map[string][]byte) string {
+	keys := make([]string, 0, len(data))
+	for k := range data {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// dataSize returns the total size of the keys and values of secret data.
+func dataSize(data map[string][]byte) int {
+	n := 0
+	for k, v := range data {
+		n += len(k) + len(v)
+	}
+	return n
+}
+
+// tlsKeys are the data keys of a kubernetes.io/tls secret that the controller
+// has always managed on target secrets.
+var tlsKeys = []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey, "ca.crt"}
+
+// selectKeys returns the subset of src to copy into the target secret. When
+// include is non-empty only those keys are copied (missing ones are skipped);
+// otherwise all keys except those in exclude are copied. An empty result is
+// rejected so the target never ends up without data.
+func selectKeys(src map[string][]byte, include, exclude []string) (map[string][]byte, error) {
+	out := map[string][]byte{}
+	if len(include) > 0 {
+		for _, k := range include {
+			if v, ok := src[k]; ok {
+				out[k] = v
+			}
+		}
+	} else {
+		skip := map[string]bool{}
+		for _, k := range exclude {
+			skip[k] = true
+		}
+		for k, v := range src {
+			if !skip[k] {
+				out[k] = v
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("key selection (include=%v, exclude=%v) leaves no data to copy", include, exclude)
+	}
+	return out, nil
+}
+
+// mapKeys renames the keys of data according to keyMap (source key to
+// target key); keys not in keyMap keep their name. Every key in keyMap must
+// be present in data, and no two keys may end up with the same name. The
+// result is always a new map, so adding keys to it leaves data unchanged.
+func mapKeys(data map[string][]byte, keyMap map[string]string) (map[string][]byte, error) {
+	for from := range keyMap {
+		if _, ok := data[from]; !ok {
+			return nil, fmt.Errorf("keyMap renames key %q, which is not in the copied data", from)
+		}
+	}
+	out := make(map[string][]byte, len(data))
+	for k, v := range data {
+		to := k
+		if mapped, ok := keyMap[k]; ok {
+			to = mapped
+		}
+		if _, dup := out[to]; dup {
+			return nil, fmt.Errorf("keyMap maps more than one key to %q", to)
+		}
+		out[to] = v
+	}
+	return out, nil
+}
+
+// secretTypeFor returns kubernetes.io/tls when data carries a certificate and
+// key pair and Opaque otherwise (e.g. a CA-only bundle).
+func secretTypeFor(data map[string][]byte) corev1.SecretType {
+	_, hasCrt := data[corev1.TLSCertKey]
+	_, hasKey := data[corev1.TLSPrivateKeyKey]
+	if hasCrt && hasKey {
+		return corev1.SecretTypeTLS
+	}
+	return corev1.SecretTypeOpaque
+}
+
+// targetSecretType resolves the type of the target secret from the requested
+// spec.targetType, inferring it from data when unset: data with a
+// .dockerconfigjson key is mirrored as a pull secret. kubernetes.io/tls is
+// only accepted when data carries both tls.crt and tls.key, and
+// kubernetes.io/dockerconfigjson only with a valid .dockerconfigjson.
+func targetSecretType(requested corev1.SecretType, data map[string][]byte) (corev1.SecretType, error) {
+	if requested == "" {
+		if _, ok := data[corev1.DockerConfigJsonKey]; ok {
+			requested = corev1.SecretTypeDockerConfigJson
+		} else {
+			return secretTypeFor(data), nil
+		}
+	}
+	switch requested {
+	case corev1.SecretTypeTLS:
+		if secretTypeFor(data) != corev1.SecretTypeTLS {
+			return "", fmt.Errorf("target type %s requires %s and %s in the copied data", requested, corev1.TLSCertKey, corev1.TLSPrivateKeyKey)
+		}
+		return requested, nil
+	case corev1.SecretTypeDockerConfigJson:
+		if !json.Valid(data[corev1.DockerConfigJsonKey]) {
+			return "", fmt.Errorf("target type %s requires valid JSON in %s of the copied data", requested, corev1.DockerConfigJsonKey)
+		}
+		return requested, nil
+	case corev1.SecretTypeOpaque:
+		return requested, nil
+	default:
+		return "", fmt.Errorf("unsupported target type %q", requested)
+	}
+}
+
+// applyTargetMetadata merges spec.targetLabels and spec.targetAnnotations of
+// the import into meta. Keys applied on a previous sync but no longer in the
+// spec are removed; keys set by others are left alone.
+func applyTargetMetadata(meta *metav1.ObjectMeta, imp *unstructured.Unstructured) {
+	if meta.Labels == nil {
+		meta.Labels = map[string]string{}
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	mergeManaged(meta.Labels, getStringMap(imp.Object, "spec.targetLabels"), meta.Annotations, managedLabelsAnnotation)
+	mergeManaged(meta.Annotations, getStringMap(imp.Object, "spec.targetAnnotations"), meta.Annotations, managedAnnotationsAnnotation)
+	meta.Annotations[managedByAnnotation] = fmt.Sprintf("%s/%s", imp.GetNamespace(), imp.GetName())
+	if len(meta.Labels) == 0 {
+		meta.Labels = nil
+	}
+	if len(meta.Annotations) == 0 {
+		meta.Annotations = nil
+	}
+}
+
+// setSourceAnnotations stamps a target secret with the export it was copied
+// from and that export's source secret, replacing any previous provenance.
+func setSourceAnnotations(meta map[string]string, expKind string, expKey, srcKey types.NamespacedName) {
+	if expKind == "ClusterCertificateExport" {
+		meta[sourceExportAnnotation] = clusterExportPrefix + expKey.Name
+	} else {
+		meta[sourceExportAnnotation] = expKey.String()
+	}
+	meta[sourceNamespaceAnnotation] = srcKey.Namespace
+	meta[sourceSecretAnnotation] = srcKey.Name
+}
+
+// mergeManaged applies desired onto target in place, first removing keys that
+// the tracking annotation trackKey lists but desired no longer contains. The
+// tracking annotation is then updated to the keys just applied.
+func mergeManaged(target, desired, annotations map[string]string, trackKey string) {
+	if prev := annotations[trackKey]; prev != "" {
+		for _, k := range strings.Split(prev, ",") {
+			if _, ok := desired[k]; !ok {
+				delete(target, k)
+			}
+		}
+	}
+	applied := make([]string, 0, len(desired))
+	for k, v := range desired {
+		if strings.HasPrefix(k, annotationPrefix) {
+			continue
+		}
+		target[k] = v
+		applied = append(applied, k)
+	}
+	sort.Strings(applied)
+	if len(applied) > 0 {
+		annotations[trackKey] = strings.Join(applied, ",")
+	} else {
+		delete(annotations, trackKey)
+	}
+}
+
 // helpers
 func schemaGVK(kind string) schema.GroupVersionKind {
 	return schema.GroupVersion{Group: crdGroup, Version: crdVersion}.WithKind(kind)
@@ -362,20 +1399,165 @@ func schemaGVKList(kind string) schema.GroupVersionKind {
 	return schema.GroupVersion{Group: crdGroup, Version: crdVersion}.WithKind(kind + "List")
 }
 
-func getString(obj map[string]interface{}, path string) string {
-	parts := strings.Split(path, ".")
+// nextSyncTime returns when the schedule of the import or export obj next
+// fires after now, including the jitter delay of an import.
+func (s *SyncController) nextSyncTime(obj *unstructured.Unstructured, now time.Time) (time.Time, error) {
+	if obj.GetKind() == "CertificateExport" {
+		return nextExportSyncTime(obj, now)
+	}
+	imp, err := toImport(obj)
+	if err != nil {
+		return time.Time{}, err
+	}
+	spec, err := scheduleFor(imp.Spec.Schedule, imp.Spec.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sched, err := parseSchedule(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	jitter, err := s.importJitter(imp)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(now).Add(jitterDelay(string(imp.UID), jitter)), nil
+}
+
+// nextExportSyncTime returns when the schedule of the export obj next fires
+// after now. Exports have no jitter.
+func nextExportSyncTime(obj *unstructured.Unstructured, now time.Time) (time.Time, error) {
+	exp, err := toExport(obj)
+	if err != nil {
+		return time.Time{}, err
+	}
+	spec, err := scheduleFor(exp.Spec.Schedule, exp.Spec.Timezone)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sched, err := parseSchedule(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return sched.Next(now), nil
+}
+
+// setNextSyncTime sets status.nextSyncTime of an import or export and reports
+// whether it changed.
+func setNextSyncTime(obj *unstructured.Unstructured, next time.Time) bool {
+	value := next.UTC().Format(time.RFC3339)
+	if getString(obj.Object, "status.nextSyncTime") == value {
+		return false
+	}
+	setString(obj.Object, "status.nextSyncTime", value)
+	return true
+}
+
+// markSynced records a successful sync in the status of obj: the time, the
+// generation of the spec that was acted upon and the number of syncs.
+func markSynced(obj *unstructured.Unstructured) {
+	setString(obj.Object, "status.lastSyncTime", time.Now().UTC().Format(time.RFC3339))
+	_ = unstructured.SetNestedField(obj.Object, obj.GetGeneration(), "status", "observedGeneration")
+	_ = unstructured.SetNestedField(obj.Object, getInt(obj.Object, "status.syncCount", 0)+1, "status", "syncCount")
+}
+
+// getValue returns the value at path, a dot-separated list of map keys and
+// list indexes (e.g. "spec.targetNamespaces.0"), and whether it is present.
+func getValue(obj map[string]interface{}, path string) (interface{}, bool) {
 	var cur interface{} = obj
-	for _, p := range parts {
-		m, ok := cur.(map[string]interface{})
-		if !ok {
-			return ""
+	for _, p := range strings.Split(path, ".") {
+		switch c := cur.(type) {
+		case map[string]interface{}:
+			v, ok := c[p]
+			if !ok {
+				return nil, false
+			}
+			cur = v
+		case []interface{}:
+			i, err := strconv.Atoi(p)
+			if err != nil || i < 0 || i >= len(c) {
+				return nil, false
+			}
+			cur = c[i]
+		default:
+			return nil, false
+		}
+	}
+	return cur, cur != nil
+}
+
+// getString returns the string at path, or "" when it is absent or not a
+// string.
+func getString(obj map[string]interface{}, path string) string {
+	s, _ := getValue(obj, path)
+	str, _ := s.(string)
+	return str
+}
+
+// getStringSlice returns the strings of the list at path, skipping other
+// items, or nil when it is absent or not a list.
+func getStringSlice(obj map[string]interface{}, path string) []string {
+	v, _ := getValue(obj, path)
+	items, ok := v.([]interface{})
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(items))
+	for _, it := range items {
+		if s, ok := it.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// getStringMap returns the string values of the map at path, skipping
+// others, or nil when it is absent or not a map.
+func getStringMap(obj map[string]interface{}, path string) map[string]string {
+	v, _ := getValue(obj, path)
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	out := make(map[string]string, len(m))
+	for k, v := range m {
+		if s, ok := v.(string); ok {
+			out[k] = s
 		}
-		cur = m[p]
 	}
-	if s, ok := cur.(string); ok {
-		return s
+	return out
+}
+
+// getBool returns the boolean at path, or def when it is absent.
+func getBool(obj map[string]interface{}, path string, def bool) bool {
+	v, _ := getValue(obj, path)
+	if b, ok := v.(bool); ok {
+		return b
+	}
+	return def
+}
+
+// getInt returns the integer at path, or def when it is absent. Whole
+// numbers decoded as floats, as JSON decoders may produce, are accepted.
+func getInt(obj map[string]interface{}, path string, def int64) int64 {
+	v, _ := getValue(obj, path)
+	switch n := v.(type) {
+	case int64:
+		return n
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case float64:
+		if n == math.Trunc(n) {
+			return int64(n)
+		}
+	case json.Number:
+		if i, err := n.Int64(); err == nil {
+			return i
+		}
 	}
-	return ""
+	return def
 }
 
 func setString(obj map[string]interface{}, path, value string) {
@@ -395,23 +1577,75 @@ func setString(obj map[string]interface{}, path, value string) {
 	}
 }
 
-func (s *SyncController) createResourceHash(exports, imports []unstructured.Unstructured) string {
-	var hashInput strings.Builder
+// sortByNamespacedName sorts items by namespace, then name.
+func sortByNamespacedName(items []unstructured.Unstructured) {
+	sort.Slice(items, func(i, j int) bool {
+		if items[i].GetNamespace() != items[j].GetNamespace() {
+			return items[i].GetNamespace() < items[j].GetNamespace()
+		}
+		return items[i].GetName() < items[j].GetName()
+	})
+}
+
+// hashRecord is the per-resource input of createResourceHash.
+type hashRecord struct {
+	Kind      string            `json:"kind"`
+	Namespace string            `json:"namespace"`
+	Name      string            `json:"name"`
+	Spec      map[string]string `json:"spec"`
+}
 
-	// Add export specs to hash
+// createResourceHash hashes the scheduling-relevant spec fields of exports
+// and imports. The records are JSON-encoded so separators inside names or
+// values can't make distinct sets collide, and sorted so list order doesn't
+// change the hash.
+func (s *SyncController) createResourceHash(exports, imports []unstructured.Unstructured) string {
+	records := make([]hashRecord, 0, len(exports)+len(imports))
 	for _, item := range exports {
-		hashInput.WriteString(fmt.Sprintf("export:%s/%s:", item.GetNamespace(), item.GetName()))
-		hashInput.WriteString(fmt.Sprintf("secretRef:%s:", getString(item.Object, "spec.secretRef")))
+		record := hashRecord{
+			Kind: "export", Namespace: item.GetNamespace(), Name: item.GetName(),
+			Spec: map[string]string{
+				"secretRef":    getString(item.Object, "spec.secretRef"),
+				"targetSecret": getString(item.Object, "spec.targetSecret"),
+				"schedule":     getString(item.Object, "spec.schedule"),
+				"timezone":     getString(item.Object, "spec.timezone"),
+			},
+		}
+		if item.GetKind() == "ClusterCertificateExport" {
+			// only cluster exports with a trust bundle are scheduled
+			record.Kind = "clusterExport"
+			record.Spec["sourceNamespace"] = getString(item.Object, "spec.sourceNamespace")
+			record.Spec["clusterTrustBundle"] = getString(item.Object, "spec.clusterTrustBundle.name")
+			record.Spec["signerName"] = getString(item.Object, "spec.clusterTrustBundle.signerName")
+		}
+		records = append(records, record)
 	}
-
-	// Add import specs to hash
 	for _, item := range imports {
-		hashInput.WriteString(fmt.Sprintf("import:%s/%s:", item.GetNamespace(), item.GetName()))
-		hashInput.WriteString(fmt.Sprintf("fromExport:%s:", getString(item.Object, "spec.fromExport")))
-		hashInput.WriteString(fmt.Sprintf("targetSecret:%s:", getString(item.Object, "spec.targetSecret")))
-		hashInput.WriteString(fmt.Sprintf("schedule:%s:", getString(item.Object, "spec.schedule")))
+		records = append(records, hashRecord{
+			Kind: "import", Namespace: item.GetNamespace(), Name: item.GetName(),
+			Spec: map[string]string{
+				"fromExport":      getString(item.Object, "spec.fromExport"),
+				"targetSecret":    getString(item.Object, "spec.targetSecret"),
+				"targetConfigMap": getString(item.Object, "spec.targetConfigMap"),
+				"schedule":        getString(item.Object, "spec.schedule"),
+				"timezone":        getString(item.Object, "spec.timezone"),
+				"jitter":          getString(item.Object, "spec.jitter"),
+			},
+		})
 	}
+	sort.Slice(records, func(i, j int) bool {
+		a, b := records[i], records[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Name < b.Name
+	})
 
-	hash := sha256.Sum256([]byte(hashInput.String()))
+	// Marshalling strings and maps of strings can't fail
+	raw, _ := json.Marshal(records)
+	hash := sha256.Sum256(raw)
 	return fmt.Sprintf("%x", hash)
 }
@@ -0,0 +1,1357 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	cron "github.com/robfig/cron/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+)
+
+// newTestScheme returns a scheme with the core types and the typed API
+// registered, as the manager's.
+func newTestScheme(t *testing.T) *runtime.Scheme {
+	t.Helper()
+	s := runtime.NewScheme()
+	if err := clientgoscheme.AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddToScheme(s); err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+// newTestController returns a SyncController backed by a fake client seeded
+// with objs. Imports and exports are read through their unstructured GVKs,
+// as in the controller, and have a status subresource.
+func newTestController(t *testing.T, opts Options, objs ...client.Object) (*SyncController, client.Client) {
+	t.Helper()
+	return newInterceptedTestController(t, opts, interceptor.Funcs{}, objs...)
+}
+
+// newInterceptedTestController is newTestController with funcs intercepting
+// the calls to the fake client, e.g. to inject API errors. The returned
+// client is the intercepted one.
+func newInterceptedTestController(t *testing.T, opts Options, funcs interceptor.Funcs, objs ...client.Object) (*SyncController, client.Client) {
+	t.Helper()
+	scheme := newTestScheme(t)
+	var withStatus []client.Object
+	for _, kind := range []string{"CertificateImport", "CertificateExport", "ClusterCertificateExport"} {
+		u := &unstructured.Unstructured{}
+		u.SetGroupVersionKind(schemaGVK(kind))
+		withStatus = append(withStatus, u)
+	}
+	c := fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(withStatus...).
+		WithInterceptorFuncs(funcs).
+		Build()
+	return NewSyncController(c, nil, scheme, nil, opts), c
+}
+
+// newExport returns a CertificateExport of the secret secretRef.
+func newExport(namespace, name, secretRef string) *unstructured.Unstructured {
+	exp := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{"secretRef": secretRef},
+	}}
+	exp.SetGroupVersionKind(schemaGVK("CertificateExport"))
+	exp.SetNamespace(namespace)
+	exp.SetName(name)
+	return exp
+}
+
+// newImport returns a CertificateImport with spec.
+func newImport(namespace, name string, spec map[string]interface{}) *unstructured.Unstructured {
+	imp := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	imp.SetNamespace(namespace)
+	imp.SetName(name)
+	imp.SetUID(types.UID(namespace + "-" + name))
+	return imp
+}
+
+// newSecret returns a secret of secretType holding data.
+func newSecret(namespace, name string, secretType corev1.SecretType, data map[string][]byte) *corev1.Secret {
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Type:       secretType,
+		Data:       data,
+	}
+}
+
+// newKeyPair returns a self-signed PEM certificate and its PEM key, valid
+// from an hour ago for a day.
+func newKeyPair(t *testing.T, cn string) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber:          big.NewInt(time.Now().UnixNano()),
+		Subject:               pkix.Name{CommonName: cn},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(24 * time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}),
+		pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+}
+
+// getSecret returns the secret namespace/name, or nil if it does not exist.
+func getSecret(t *testing.T, c client.Client, namespace, name string) *corev1.Secret {
+	t.Helper()
+	var secret corev1.Secret
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, &secret); err != nil {
+		if client.IgnoreNotFound(err) == nil {
+			return nil
+		}
+		t.Fatal(err)
+	}
+	return &secret
+}
+
+// getImportCondition returns the condition condType of the import
+// namespace/name, or nil if it is not set.
+func getImportCondition(t *testing.T, c client.Client, namespace, name, condType string) *metav1.Condition {
+	t.Helper()
+	return getCondition(t, c, "CertificateImport", namespace, name, condType)
+}
+
+// getExportCondition is getImportCondition for a CertificateExport.
+func getExportCondition(t *testing.T, c client.Client, namespace, name, condType string) *metav1.Condition {
+	t.Helper()
+	return getCondition(t, c, "CertificateExport", namespace, name, condType)
+}
+
+func getCondition(t *testing.T, c client.Client, kind, namespace, name, condType string) *metav1.Condition {
+	t.Helper()
+	obj := &unstructured.Unstructured{}
+	obj.SetGroupVersionKind(schemaGVK(kind))
+	if err := c.Get(context.Background(), types.NamespacedName{Namespace: namespace, Name: name}, obj); err != nil {
+		t.Fatal(err)
+	}
+	for _, cond := range getConditions(obj) {
+		if cond.Type == condType {
+			return &cond
+		}
+	}
+	return nil
+}
+
+func TestSyncImport(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	ca, _ := newKeyPair(t, "ca")
+	tlsData := func(extra map[string][]byte) map[string][]byte {
+		data := map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}
+		for k, v := range extra {
+			data[k] = v
+		}
+		return data
+	}
+	importSpec := map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}
+
+	tests := []struct {
+		name string
+		objs []client.Object
+		// change is applied to the cluster after a first successful sync,
+		// which is followed by a second one
+		change   func(t *testing.T, c client.Client)
+		wantErr  error
+		wantType corev1.SecretType
+		wantKeys []string
+	}{
+		{
+			name: "creates the target",
+			objs: []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, tlsData(nil)),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", importSpec),
+			},
+			wantType: corev1.SecretTypeTLS,
+			wantKeys: []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+		},
+		{
+			name: "updates the target after a rotation",
+			objs: []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, tlsData(nil)),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", importSpec),
+			},
+			change: func(t *testing.T, c client.Client) {
+				crt2, key2 := newKeyPair(t, "app-rotated")
+				src := getSecret(t, c, "backend", "app-tls")
+				src.Data = map[string][]byte{corev1.TLSCertKey: crt2, corev1.TLSPrivateKeyKey: key2}
+				if err := c.Update(context.Background(), src); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantType: corev1.SecretTypeTLS,
+			wantKeys: []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+		},
+		{
+			name: "fails on a missing export",
+			objs: []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, tlsData(nil)),
+				newImport("frontend", "app", importSpec),
+			},
+			wantErr: ErrExportNotFound,
+		},
+		{
+			name: "fails on a missing source secret",
+			objs: []client.Object{
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", importSpec),
+			},
+			wantErr: ErrSourceSecretMissing,
+		},
+		{
+			name: "fails on a source of the wrong type",
+			objs: []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeOpaque, tlsData(nil)),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", importSpec),
+			},
+			wantErr: ErrWrongSecretType,
+		},
+		{
+			name: "adds ca.crt once the source has it",
+			objs: []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, tlsData(nil)),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", importSpec),
+			},
+			change: func(t *testing.T, c client.Client) {
+				src := getSecret(t, c, "backend", "app-tls")
+				src.Data = tlsData(map[string][]byte{"ca.crt": ca})
+				if err := c.Update(context.Background(), src); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantType: corev1.SecretTypeTLS,
+			wantKeys: []string{"ca.crt", corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+		},
+		{
+			name: "removes ca.crt once the source drops it",
+			objs: []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, tlsData(map[string][]byte{"ca.crt": ca})),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", importSpec),
+			},
+			change: func(t *testing.T, c client.Client) {
+				src := getSecret(t, c, "backend", "app-tls")
+				src.Data = tlsData(nil)
+				if err := c.Update(context.Background(), src); err != nil {
+					t.Fatal(err)
+				}
+			},
+			wantType: corev1.SecretTypeTLS,
+			wantKeys: []string{corev1.TLSCertKey, corev1.TLSPrivateKeyKey},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestController(t, Options{}, tt.objs...)
+			ctx := context.Background()
+			err := s.syncImport(ctx, "frontend", "app")
+			if tt.change != nil {
+				if err != nil {
+					t.Fatalf("first sync: %v", err)
+				}
+				tt.change(t, c)
+				err = s.syncImport(ctx, "frontend", "app")
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("got error %v, want %v", err, tt.wantErr)
+				}
+				if tgt := getSecret(t, c, "frontend", "app-tls"); tgt != nil {
+					t.Fatalf("target was written despite the error: %v", tgt.Data)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			src := getSecret(t, c, "backend", "app-tls")
+			tgt := getSecret(t, c, "frontend", "app-tls")
+			if tgt == nil {
+				t.Fatal("target secret was not created")
+			}
+			if tgt.Type != tt.wantType {
+				t.Errorf("got type %s, want %s", tgt.Type, tt.wantType)
+			}
+			if got := joinKeys(tgt.Data); got != joinKeys(keySet(tt.wantKeys)) {
+				t.Errorf("got keys %s, want %v", got, tt.wantKeys)
+			}
+			for _, k := range tt.wantKeys {
+				if string(tgt.Data[k]) != string(src.Data[k]) {
+					t.Errorf("%s of the target does not match the source", k)
+				}
+			}
+			if got := tgt.Annotations[managedByAnnotation]; got != "frontend/app" {
+				t.Errorf("got managed-by %q, want frontend/app", got)
+			}
+		})
+	}
+}
+
+// keySet returns data with the given keys and empty values, for comparing
+// key sets with joinKeys.
+func keySet(keys []string) map[string][]byte {
+	data := make(map[string][]byte, len(keys))
+	for _, k := range keys {
+		data[k] = nil
+	}
+	return data
+}
+
+func TestTargetSecretType(t *testing.T) {
+	tlsPair := map[string][]byte{corev1.TLSCertKey: []byte("crt"), corev1.TLSPrivateKeyKey: []byte("key")}
+	caOnly := map[string][]byte{"ca.crt": []byte("ca")}
+	tests := []struct {
+		name      string
+		requested corev1.SecretType
+		data      map[string][]byte
+		want      corev1.SecretType
+		wantErr   bool
+	}{
+		{name: "infers tls from the key pair", data: tlsPair, want: corev1.SecretTypeTLS},
+		{name: "infers Opaque without the key pair", data: caOnly, want: corev1.SecretTypeOpaque},
+		{name: "infers dockerconfigjson", data: map[string][]byte{corev1.DockerConfigJsonKey: []byte(`{}`)}, want: corev1.SecretTypeDockerConfigJson},
+		{name: "keeps Opaque for a key pair", requested: corev1.SecretTypeOpaque, data: tlsPair, want: corev1.SecretTypeOpaque},
+		{name: "rejects tls without the key pair", requested: corev1.SecretTypeTLS, data: caOnly, wantErr: true},
+		{name: "rejects invalid dockerconfigjson", requested: corev1.SecretTypeDockerConfigJson, data: map[string][]byte{corev1.DockerConfigJsonKey: []byte("{")}, wantErr: true},
+		{name: "rejects other types", requested: corev1.SecretTypeBasicAuth, data: tlsPair, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := targetSecretType(tt.requested, tt.data)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("got type %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSyncImportOpaqueTarget(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	ca, _ := newKeyPair(t, "ca")
+	src := newSecret("backend", "app-tls", corev1.SecretTypeTLS,
+		map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key, "ca.crt": ca})
+
+	tests := []struct {
+		name     string
+		spec     map[string]interface{}
+		wantType corev1.SecretType
+		wantErr  bool
+	}{
+		{
+			name:     "CA bundle only",
+			spec:     map[string]interface{}{"includeKeys": []interface{}{"ca.crt"}},
+			wantType: corev1.SecretTypeOpaque,
+		},
+		{
+			name:     "explicit Opaque for the key pair",
+			spec:     map[string]interface{}{"targetType": "Opaque"},
+			wantType: corev1.SecretTypeOpaque,
+		},
+		{
+			name:    "tls requested for a CA bundle",
+			spec:    map[string]interface{}{"includeKeys": []interface{}{"ca.crt"}, "targetType": "kubernetes.io/tls"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.spec["fromExport"] = "backend/app"
+			tt.spec["targetSecret"] = "app-ca"
+			s, c := newTestController(t, Options{},
+				src.DeepCopy(), newExport("backend", "app", "app-tls"), newImport("frontend", "app", tt.spec))
+			err := s.syncImport(context.Background(), "frontend", "app")
+			tgt := getSecret(t, c, "frontend", "app-ca")
+			if tt.wantErr {
+				if err == nil {
+					t.Fatal("sync succeeded, want an error")
+				}
+				if tgt != nil {
+					t.Fatalf("target was written despite the error: %v", tgt.Type)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if tgt == nil || tgt.Type != tt.wantType {
+				t.Fatalf("got target %v, want type %s", tgt, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestSyncImportTargetLabels(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx := context.Background()
+
+	// each step sets spec.targetLabels and syncs; steps run in order
+	steps := []struct {
+		name   string
+		labels map[string]interface{}
+		want   map[string]string
+	}{
+		{
+			name:   "adds a label",
+			labels: map[string]interface{}{"app": "web"},
+			want:   map[string]string{"app": "web", "owner": "ops"},
+		},
+		{
+			name:   "updates a label",
+			labels: map[string]interface{}{"app": "api"},
+			want:   map[string]string{"app": "api", "owner": "ops"},
+		},
+		{
+			name:   "removes a label dropped from the spec",
+			labels: nil,
+			want:   map[string]string{"owner": "ops"},
+		},
+	}
+	for i, step := range steps {
+		var imp unstructured.Unstructured
+		imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "frontend", Name: "app"}, &imp); err != nil {
+			t.Fatal(err)
+		}
+		if step.labels == nil {
+			unstructured.RemoveNestedField(imp.Object, "spec", "targetLabels")
+		} else if err := unstructured.SetNestedMap(imp.Object, step.labels, "spec", "targetLabels"); err != nil {
+			t.Fatal(err)
+		}
+		if err := c.Update(ctx, &imp); err != nil {
+			t.Fatal(err)
+		}
+		if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		tgt := getSecret(t, c, "frontend", "app-tls")
+		if i == 0 {
+			// a label set by someone else is never touched
+			tgt.Labels["owner"] = "ops"
+			if err := c.Update(ctx, tgt); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if !reflect.DeepEqual(tgt.Labels, step.want) {
+			t.Errorf("%s: got labels %v, want %v", step.name, tgt.Labels, step.want)
+		}
+	}
+}
+
+func TestSyncImportOwnerReference(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	pair := map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}
+	importSpec := map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}
+	adopting := newImport("frontend", "app", importSpec)
+	adopting.SetAnnotations(map[string]string{adoptAnnotation: "true"})
+
+	tests := []struct {
+		name string
+		imp  *unstructured.Unstructured
+		// existing is the target secret before the sync, if any
+		existing  *corev1.Secret
+		wantErr   error
+		wantOwned bool
+	}{
+		{
+			name:      "owns a created target",
+			imp:       newImport("frontend", "app", importSpec),
+			wantOwned: true,
+		},
+		{
+			name:     "leaves an unmanaged target alone",
+			imp:      newImport("frontend", "app", importSpec),
+			existing: newSecret("frontend", "app-tls", corev1.SecretTypeOpaque, map[string][]byte{"hand": []byte("made")}),
+			wantErr:  ErrTargetNotManaged,
+		},
+		{
+			name:      "adopts an unmanaged target on request",
+			imp:       adopting,
+			existing:  newSecret("frontend", "app-tls", corev1.SecretTypeOpaque, map[string][]byte{"hand": []byte("made")}),
+			wantOwned: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			objs := []client.Object{
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, pair),
+				newExport("backend", "app", "app-tls"),
+				tt.imp,
+			}
+			if tt.existing != nil {
+				objs = append(objs, tt.existing)
+			}
+			s, c := newTestController(t, Options{}, objs...)
+			err := s.syncImport(context.Background(), "frontend", "app")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("got error %v, want %v", err, tt.wantErr)
+			}
+			tgt := getSecret(t, c, "frontend", "app-tls")
+			owner := metav1.GetControllerOf(tgt)
+			if got := owner != nil && owner.UID == tt.imp.GetUID() && owner.Kind == "CertificateImport"; got != tt.wantOwned {
+				t.Errorf("got controller %+v, want owned by the import: %v", owner, tt.wantOwned)
+			}
+			if tt.wantErr != nil && string(tgt.Data["hand"]) != "made" {
+				t.Errorf("unmanaged target was modified: %v", tgt.Data)
+			}
+		})
+	}
+}
+
+func TestSyncImportUnmanagedTarget(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	handMade := map[string][]byte{"password": []byte("hunter2")}
+
+	tests := []struct {
+		name      string
+		managedBy string
+	}{
+		{name: "secret without managed-by"},
+		{name: "secret of another import", managedBy: "frontend/other"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			existing := newSecret("frontend", "app-tls", corev1.SecretTypeOpaque, handMade)
+			if tt.managedBy != "" {
+				existing.Annotations = map[string]string{managedByAnnotation: tt.managedBy}
+			}
+			s, c := newTestController(t, Options{},
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+				existing,
+			)
+			ctx := context.Background()
+
+			if err := s.syncImport(ctx, "frontend", "app"); !errors.Is(err, ErrTargetNotManaged) {
+				t.Fatalf("got error %v, want %v", err, ErrTargetNotManaged)
+			}
+			tgt := getSecret(t, c, "frontend", "app-tls")
+			if tgt.Type != corev1.SecretTypeOpaque || !reflect.DeepEqual(tgt.Data, handMade) {
+				t.Fatalf("unmanaged secret was overwritten: %s %v", tgt.Type, tgt.Data)
+			}
+			cond := getImportCondition(t, c, "frontend", "app", conditionConflict)
+			if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonTargetNotManaged {
+				t.Fatalf("got Conflict condition %+v, want True/%s", cond, reasonTargetNotManaged)
+			}
+
+			// once the secret is handed over the sync goes ahead and clears
+			// the condition
+			tgt.Annotations = map[string]string{managedByAnnotation: "frontend/app"}
+			if err := c.Update(ctx, tgt); err != nil {
+				t.Fatal(err)
+			}
+			if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+				t.Fatal(err)
+			}
+			if cond := getImportCondition(t, c, "frontend", "app", conditionConflict); cond != nil {
+				t.Errorf("Conflict condition was not cleared: %+v", cond)
+			}
+		})
+	}
+}
+
+func TestSyncImportVerifyKeyPair(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	_, otherKey := newKeyPair(t, "other")
+
+	tests := []struct {
+		name      string
+		crt, key  []byte
+		noVerify  bool
+		wantErr   bool
+		wantEvent string
+	}{
+		{name: "valid pair", crt: crt, key: key, wantEvent: "Normal " + eventReasonSynced},
+		{name: "mismatched pair", crt: crt, key: otherKey, wantErr: true, wantEvent: "Warning " + eventReasonInvalidCertificate},
+		{name: "malformed PEM", crt: []byte("-----BEGIN CERTIFICATE-----\nnot base64\n-----END CERTIFICATE-----\n"), key: key, wantErr: true, wantEvent: "Warning " + eventReasonInvalidCertificate},
+		{name: "mismatched pair without verifyKeyPair", crt: crt, key: otherKey, noVerify: true, wantEvent: "Normal " + eventReasonSynced},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}
+			if tt.noVerify {
+				spec["verifyKeyPair"] = false
+			}
+			s, c := newTestController(t, Options{},
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: tt.crt, corev1.TLSPrivateKeyKey: tt.key}),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", spec),
+			)
+			recorder := record.NewFakeRecorder(10)
+			s.recorder = recorder
+
+			err := s.syncImport(context.Background(), "frontend", "app")
+			if tt.wantErr && !errors.Is(err, ErrInvalidCertificate) || !tt.wantErr && err != nil {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if got := getSecret(t, c, "frontend", "app-tls") != nil; got == tt.wantErr {
+				t.Errorf("got target written %v, want %v", got, !tt.wantErr)
+			}
+			cond := getImportCondition(t, c, "frontend", "app", conditionInvalidCertificate)
+			if tt.wantErr != (cond != nil) || cond != nil && cond.Reason != reasonInvalidKeyPair {
+				t.Errorf("got InvalidCertificate condition %+v", cond)
+			}
+			select {
+			case event := <-recorder.Events:
+				if !strings.HasPrefix(event, tt.wantEvent+" ") {
+					t.Errorf("got event %q, want %s", event, tt.wantEvent)
+				}
+			default:
+				t.Errorf("no event recorded, want %s", tt.wantEvent)
+			}
+		})
+	}
+}
+
+func TestSyncImportSkipsUnchangedWrite(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+	)
+	ctx := context.Background()
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	first := getSecret(t, c, "frontend", "app-tls")
+	if got, want := first.Annotations[checksumAnnotation], dataChecksum(first.Data); got != want {
+		t.Fatalf("got checksum annotation %q, want %q", got, want)
+	}
+
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if second := getSecret(t, c, "frontend", "app-tls"); second.ResourceVersion != first.ResourceVersion {
+		t.Errorf("unchanged source updated the target: resourceVersion %s -> %s", first.ResourceVersion, second.ResourceVersion)
+	}
+
+	// an edit of the target is not covered by its checksum and is overwritten
+	edited := first.DeepCopy()
+	edited.Data[corev1.TLSCertKey] = []byte("edited")
+	if err := c.Update(ctx, edited); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if got := getSecret(t, c, "frontend", "app-tls").Data[corev1.TLSCertKey]; string(got) != string(crt) {
+		t.Errorf("edited target was not restored: %q", got)
+	}
+}
+
+func TestBuildSchedulesKeepsUnchangedEntries(t *testing.T) {
+	scheduled := func(name, schedule string) *unstructured.Unstructured {
+		return newImport("frontend", name, map[string]interface{}{
+			"fromExport": "backend/app", "targetSecret": name + "-tls", "schedule": schedule,
+		})
+	}
+	s, c := newTestController(t, Options{},
+		scheduled("a", "0 * * * *"), scheduled("b", "0 * * * *"), scheduled("c", "0 * * * *"))
+	ctx := context.Background()
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	before := map[string]cron.EntryID{}
+	for key, entry := range s.scheduled {
+		before[key] = entry.id
+	}
+	if len(before) != 3 {
+		t.Fatalf("got %d entries, want 3: %v", len(before), before)
+	}
+
+	// reschedule b and delete c
+	var imp unstructured.Unstructured
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "frontend", Name: "b"}, &imp); err != nil {
+		t.Fatal(err)
+	}
+	if err := unstructured.SetNestedField(imp.Object, "30 * * * *", "spec", "schedule"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Update(ctx, &imp); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Get(ctx, types.NamespacedName{Namespace: "frontend", Name: "c"}, &imp); err != nil {
+		t.Fatal(err)
+	}
+	// the finalizer added by the first pass is removed by the second
+	if err := c.Delete(ctx, &imp); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	keyA, keyB, keyC := scheduleKey("CertificateImport", "frontend", "a"), scheduleKey("CertificateImport", "frontend", "b"), scheduleKey("CertificateImport", "frontend", "c")
+	if got := s.scheduled[keyA].id; got != before[keyA] {
+		t.Errorf("unchanged import a was rescheduled: entry %d -> %d", before[keyA], got)
+	}
+	if got, ok := s.scheduled[keyB]; !ok || got.id == before[keyB] {
+		t.Errorf("rescheduled import b kept entry %d", before[keyB])
+	}
+	if _, ok := s.scheduled[keyC]; ok {
+		t.Error("deleted import c is still scheduled")
+	}
+	if got := len(s.cron.Entries()); got != 2 {
+		t.Errorf("got %d cron entries, want 2", got)
+	}
+}
+
+func TestCreateResourceHashSeparators(t *testing.T) {
+	// each pair would encode to the same string when joining fields with
+	// ":" or "/"
+	imp := func(namespace, name, fromExport, targetSecret string) unstructured.Unstructured {
+		return *newImport(namespace, name, map[string]interface{}{"fromExport": fromExport, "targetSecret": targetSecret})
+	}
+	tests := []struct {
+		name string
+		a, b []unstructured.Unstructured
+	}{
+		{
+			name: "separator moved between namespace and name",
+			a:    []unstructured.Unstructured{imp("a", "b:c", "x/y", "t")},
+			b:    []unstructured.Unstructured{imp("a:b", "c", "x/y", "t")},
+		},
+		{
+			name: "separator moved between spec fields",
+			a:    []unstructured.Unstructured{imp("ns", "app", "x/y:z", "t")},
+			b:    []unstructured.Unstructured{imp("ns", "app", "x/y", "z:t")},
+		},
+		{
+			name: "one import split into two",
+			a:    []unstructured.Unstructured{imp("ns", "app", "x/y", "t"), imp("ns", "web", "x/y", "t")},
+			b:    []unstructured.Unstructured{imp("ns", "app", "x/y", "t|ns:web:x/y:t")},
+		},
+	}
+	s, _ := newTestController(t, Options{})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if s.createResourceHash(nil, tt.a) == s.createResourceHash(nil, tt.b) {
+				t.Error("distinct imports hash the same")
+			}
+		})
+	}
+}
+
+func TestCreateResourceHashOrder(t *testing.T) {
+	exports := []unstructured.Unstructured{
+		*newExport("backend", "app", "app-tls"),
+		*newExport("backend", "api", "api-tls"),
+		*newExport("auth", "app", "app-tls"),
+	}
+	imports := []unstructured.Unstructured{
+		*newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		*newImport("frontend", "api", map[string]interface{}{"fromExport": "backend/api", "targetSecret": "api-tls"}),
+		*newImport("batch", "app", map[string]interface{}{"fromExport": "auth/app", "targetSecret": "app-tls"}),
+	}
+	reversed := func(items []unstructured.Unstructured) []unstructured.Unstructured {
+		out := make([]unstructured.Unstructured, 0, len(items))
+		for i := len(items) - 1; i >= 0; i-- {
+			out = append(out, items[i])
+		}
+		return out
+	}
+
+	s, _ := newTestController(t, Options{})
+	want := s.createResourceHash(exports, imports)
+	if got := s.createResourceHash(reversed(exports), reversed(imports)); got != want {
+		t.Errorf("reordered items hash to %s, want %s", got, want)
+	}
+
+	// sorting, as buildSchedules does before scheduling, is order
+	// independent too
+	a, b := append([]unstructured.Unstructured(nil), imports...), reversed(imports)
+	sortByNamespacedName(a)
+	sortByNamespacedName(b)
+	for i := range a {
+		if a[i].GetNamespace() != b[i].GetNamespace() || a[i].GetName() != b[i].GetName() {
+			t.Fatalf("sorted orders differ at %d: %s/%s and %s/%s", i, a[i].GetNamespace(), a[i].GetName(), b[i].GetNamespace(), b[i].GetName())
+		}
+	}
+	if a[0].GetNamespace() != "batch" || a[1].GetName() != "api" {
+		t.Errorf("got order %v, want by namespace, then name", importNames(a))
+	}
+}
+
+// pathTestObject is the object the path helper tests read from.
+func pathTestObject() map[string]interface{} {
+	return map[string]interface{}{
+		"spec": map[string]interface{}{
+			"schedule":         "0 * * * *",
+			"targetNamespaces": []interface{}{"a", int64(1), "b"},
+			"suspend":          true,
+			"replicas":         int64(3),
+			"ratio":            float64(2),
+			"half":             float64(2.5),
+			"count":            json.Number("7"),
+			"empty":            nil,
+			"rollout":          []interface{}{map[string]interface{}{"kind": "Deployment"}},
+		},
+	}
+}
+
+func TestGetValue(t *testing.T) {
+	tests := []struct {
+		path   string
+		want   interface{}
+		wantOK bool
+	}{
+		{path: "spec.schedule", want: "0 * * * *", wantOK: true},
+		{path: "spec.targetNamespaces.2", want: "b", wantOK: true},
+		{path: "spec.rollout.0.kind", want: "Deployment", wantOK: true},
+		{path: "spec.missing"},
+		{path: "spec.empty"},
+		{path: "spec.targetNamespaces.3"},
+		{path: "spec.targetNamespaces.-1"},
+		{path: "spec.targetNamespaces.first"},
+		{path: "spec.schedule.nested"},
+		{path: "status.schedule"},
+	}
+	for _, tt := range tests {
+		got, ok := getValue(pathTestObject(), tt.path)
+		if ok != tt.wantOK || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("getValue(%q) = %v, %v, want %v, %v", tt.path, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestGetStringSlice(t *testing.T) {
+	tests := []struct {
+		path string
+		want []string
+	}{
+		{path: "spec.targetNamespaces", want: []string{"a", "b"}},
+		{path: "spec.schedule"},
+		{path: "spec.missing"},
+	}
+	for _, tt := range tests {
+		if got := getStringSlice(pathTestObject(), tt.path); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("getStringSlice(%q) = %#v, want %#v", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestGetBool(t *testing.T) {
+	tests := []struct {
+		path string
+		def  bool
+		want bool
+	}{
+		{path: "spec.suspend", want: true},
+		{path: "spec.missing", def: true, want: true},
+		{path: "spec.missing", want: false},
+		{path: "spec.schedule", def: true, want: true},
+	}
+	for _, tt := range tests {
+		if got := getBool(pathTestObject(), tt.path, tt.def); got != tt.want {
+			t.Errorf("getBool(%q, %v) = %v, want %v", tt.path, tt.def, got, tt.want)
+		}
+	}
+}
+
+func TestGetInt(t *testing.T) {
+	tests := []struct {
+		path string
+		want int64
+	}{
+		{path: "spec.replicas", want: 3},
+		{path: "spec.ratio", want: 2},
+		{path: "spec.count", want: 7},
+		{path: "spec.half", want: -1},
+		{path: "spec.schedule", want: -1},
+		{path: "spec.missing", want: -1},
+	}
+	for _, tt := range tests {
+		if got := getInt(pathTestObject(), tt.path, -1); got != tt.want {
+			t.Errorf("getInt(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestParseNSName(t *testing.T) {
+	tests := []struct {
+		ref     string
+		want    types.NamespacedName
+		wantErr bool
+	}{
+		{ref: "app", want: types.NamespacedName{Namespace: "frontend", Name: "app"}},
+		{ref: "backend/app", want: types.NamespacedName{Namespace: "backend", Name: "app"}},
+		{ref: "  backend / app ", want: types.NamespacedName{Namespace: "backend", Name: "app"}},
+		{ref: "", wantErr: true},
+		{ref: "   ", wantErr: true},
+		{ref: "/app", wantErr: true},
+		{ref: "backend/", wantErr: true},
+		{ref: " / ", wantErr: true},
+		{ref: "backend/app/extra", wantErr: true},
+		{ref: "backend//app", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := parseNSName("frontend", tt.ref)
+		if tt.wantErr {
+			if !errors.Is(err, ErrInvalidReference) {
+				t.Errorf("parseNSName(%q) = %v, %v, want %v", tt.ref, got, err, ErrInvalidReference)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("parseNSName(%q) = %v, %v, want %v", tt.ref, got, err, tt.want)
+		}
+	}
+}
+
+func TestSyncImportDefaultFromExport(t *testing.T) {
+	if err := SetDefaultFromExport("backend/default"); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { defaultFromExport = "" })
+	defaultCrt, defaultKey := newKeyPair(t, "default")
+	crt, key := newKeyPair(t, "app")
+
+	tests := []struct {
+		name       string
+		spec       map[string]interface{}
+		wantSource string
+	}{
+		{
+			name:       "import without fromExport",
+			spec:       map[string]interface{}{"targetSecret": "app-tls"},
+			wantSource: "backend/default",
+		},
+		{
+			name:       "import with fromExport",
+			spec:       map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"},
+			wantSource: "backend/app",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, c := newTestController(t, Options{},
+				newSecret("backend", "default-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: defaultCrt, corev1.TLSPrivateKeyKey: defaultKey}),
+				newExport("backend", "default", "default-tls"),
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+				newExport("backend", "app", "app-tls"),
+				newImport("frontend", "app", tt.spec),
+			)
+			if err := s.syncImport(context.Background(), "frontend", "app"); err != nil {
+				t.Fatal(err)
+			}
+			tgt := getSecret(t, c, "frontend", "app-tls")
+			if got := tgt.Annotations[sourceExportAnnotation]; got != tt.wantSource {
+				t.Errorf("got source export %q, want %q", got, tt.wantSource)
+			}
+		})
+	}
+
+	// configmap imports list their exports and never use the default
+	cm := newImport("frontend", "bundle", map[string]interface{}{"targetConfigMap": "ca-bundle", "fromExports": []interface{}{"backend/app"}})
+	if got := fromExportOf(cm); got != "" {
+		t.Errorf("configmap import uses export %q, want none", got)
+	}
+}
+
+func TestSyncImportWaitForSourceData(t *testing.T) {
+	// a placeholder as created ahead of the controller that fills it in
+	placeholder := newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: nil, corev1.TLSPrivateKeyKey: nil})
+	s, c := newTestController(t, Options{},
+		placeholder,
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls", "waitForSourceData": true}),
+	)
+	ctx := context.Background()
+
+	if err := s.syncImport(ctx, "frontend", "app"); !errors.Is(err, ErrSourceEmpty) {
+		t.Fatalf("got error %v, want %v", err, ErrSourceEmpty)
+	}
+	if tgt := getSecret(t, c, "frontend", "app-tls"); tgt != nil {
+		t.Fatalf("empty source was mirrored: %v", tgt.Data)
+	}
+	cond := getImportCondition(t, c, "frontend", "app", conditionSourceEmpty)
+	if cond == nil || cond.Status != metav1.ConditionTrue || cond.Reason != reasonNotPopulated {
+		t.Fatalf("got SourceEmpty condition %+v, want True/%s", cond, reasonNotPopulated)
+	}
+
+	crt, key := newKeyPair(t, "app")
+	src := getSecret(t, c, "backend", "app-tls")
+	src.Data = map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}
+	if err := c.Update(ctx, src); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	if tgt := getSecret(t, c, "frontend", "app-tls"); tgt == nil || string(tgt.Data[corev1.TLSCertKey]) != string(crt) {
+		t.Fatal("populated source was not mirrored")
+	}
+	if cond := getImportCondition(t, c, "frontend", "app", conditionSourceEmpty); cond != nil {
+		t.Errorf("SourceEmpty condition was not cleared: %+v", cond)
+	}
+}
+
+func TestCreateResourceHashClusterTrustBundle(t *testing.T) {
+	clusterExport := func(bundle map[string]interface{}) unstructured.Unstructured {
+		exp := unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{
+			"sourceNamespace": "pki", "secretRef": "root-ca",
+		}}}
+		if bundle != nil {
+			exp.Object["spec"].(map[string]interface{})["clusterTrustBundle"] = bundle
+		}
+		exp.SetGroupVersionKind(schemaGVK("ClusterCertificateExport"))
+		exp.SetName("root-ca")
+		return exp
+	}
+	s, _ := newTestController(t, Options{})
+	hashes := map[string]string{}
+	for name, bundle := range map[string]map[string]interface{}{
+		"no bundle":   nil,
+		"bundle":      {"name": "root-ca"},
+		"renamed":     {"name": "root-ca-2"},
+		"with signer": {"name": "root-ca", "signerName": "example.com/root"},
+	} {
+		hash := s.createResourceHash([]unstructured.Unstructured{clusterExport(bundle)}, nil)
+		for other, h := range hashes {
+			if h == hash {
+				t.Errorf("%s and %s hash the same", name, other)
+			}
+		}
+		hashes[name] = hash
+	}
+}
+
+// eventually polls cond until it holds, failing the test after a few seconds.
+func eventually(t *testing.T, msg string, cond func() bool) {
+	t.Helper()
+	for deadline := time.Now().Add(5 * time.Second); !cond(); time.Sleep(10 * time.Millisecond) {
+		if time.Now().After(deadline) {
+			t.Fatalf("timed out waiting until %s", msg)
+		}
+	}
+}
+
+func TestPrimeImportsNewImports(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	first := newImport("frontend", "first", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "first-tls"})
+	later := newImport("frontend", "later", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "later-tls"})
+	s, c := newTestController(t, Options{ImmediateOnStart: true},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		first, later,
+	)
+	ctx := context.Background()
+
+	s.primeImports(ctx, []unstructured.Unstructured{*first})
+	eventually(t, "the first import is synced", func() bool { return getSecret(t, c, "frontend", "first-tls") != nil })
+	if err := c.Delete(ctx, getSecret(t, c, "frontend", "first-tls")); err != nil {
+		t.Fatal(err)
+	}
+
+	// an import created after startup is synced on the pass that first sees it
+	s.primeImports(ctx, []unstructured.Unstructured{*first, *later})
+	eventually(t, "the later import is synced", func() bool { return getSecret(t, c, "frontend", "later-tls") != nil })
+	if getSecret(t, c, "frontend", "first-tls") != nil {
+		t.Error("an import already primed was synced again")
+	}
+
+	// a deleted and recreated import has a new UID and is primed again
+	s.primeImports(ctx, nil)
+	if len(s.primed) != 0 {
+		t.Errorf("got %d primed imports after all were deleted, want 0", len(s.primed))
+	}
+}
+
+func TestPrimeImportsSyncOnStart(t *testing.T) {
+	tests := []struct {
+		name        string
+		immediate   bool
+		syncOnStart interface{}
+		want        bool
+	}{
+		{name: "global default on", immediate: true, want: true},
+		{name: "global default off"},
+		{name: "enabled per import", syncOnStart: true, want: true},
+		{name: "disabled per import", immediate: true, syncOnStart: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			crt, key := newKeyPair(t, "app")
+			spec := map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}
+			if tt.syncOnStart != nil {
+				spec["syncOnStart"] = tt.syncOnStart
+			}
+			imp := newImport("frontend", "app", spec)
+			// a second import that always syncs marks the end of the pass
+			marker := newImport("frontend", "marker", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "marker-tls", "syncOnStart": true})
+			s, c := newTestController(t, Options{ImmediateOnStart: tt.immediate},
+				newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+				newExport("backend", "app", "app-tls"),
+				imp, marker,
+			)
+			s.primeImports(context.Background(), []unstructured.Unstructured{*imp, *marker})
+			eventually(t, "the marker import is synced", func() bool { return getSecret(t, c, "frontend", "marker-tls") != nil })
+			if got := getSecret(t, c, "frontend", "app-tls") != nil; got != tt.want {
+				t.Errorf("got synced on start %t, want %t", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScheduleForDST(t *testing.T) {
+	utc := func(month time.Month, day, hour, min int) time.Time {
+		return time.Date(2025, month, day, hour, min, 0, 0, time.UTC)
+	}
+	// America/New_York springs forward on 2025-03-09 02:00 EST and falls
+	// back on 2025-11-02 02:00 EDT
+	tests := []struct {
+		name     string
+		schedule string
+		tz       string
+		from     time.Time
+		want     []time.Time
+	}{
+		{
+			name:     "same wall-clock time across spring forward",
+			schedule: "0 9 * * *", tz: "America/New_York",
+			from: utc(time.March, 8, 14, 0),
+			want: []time.Time{utc(time.March, 9, 13, 0), utc(time.March, 10, 13, 0)},
+		},
+		{
+			name:     "time skipped by spring forward",
+			schedule: "30 2 * * *", tz: "America/New_York",
+			from: utc(time.March, 8, 8, 0),
+			want: []time.Time{utc(time.March, 10, 6, 30), utc(time.March, 11, 6, 30)},
+		},
+		{
+			name:     "same wall-clock time across fall back",
+			schedule: "0 9 * * *", tz: "America/New_York",
+			from: utc(time.November, 1, 13, 0),
+			want: []time.Time{utc(time.November, 2, 14, 0), utc(time.November, 3, 14, 0)},
+		},
+		{
+			name:     "time repeated by fall back",
+			schedule: "30 1 * * *", tz: "America/New_York",
+			from: utc(time.November, 1, 8, 0),
+			want: []time.Time{utc(time.November, 2, 5, 30), utc(time.November, 2, 6, 30)},
+		},
+		{
+			name:     "CRON_TZ prefix",
+			schedule: "CRON_TZ=America/New_York 0 9 * * *",
+			from:     utc(time.March, 8, 14, 0),
+			want:     []time.Time{utc(time.March, 9, 13, 0), utc(time.March, 10, 13, 0)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := scheduleFor(tt.schedule, tt.tz)
+			if err != nil {
+				t.Fatal(err)
+			}
+			sched, err := parseSchedule(spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			next := tt.from
+			for i, want := range tt.want {
+				next = sched.Next(next)
+				if !next.Equal(want) {
+					t.Errorf("run %d: got %s, want %s", i+1, next.UTC(), want)
+				}
+			}
+		})
+	}
+}
+
+func TestScheduleForInvalidTimezone(t *testing.T) {
+	if _, err := scheduleFor("0 9 * * *", "Mars/Olympus_Mons"); err == nil {
+		t.Error("an unknown timezone was accepted")
+	}
+	if _, err := scheduleFor("CRON_TZ=UTC 0 9 * * *", "America/New_York"); err == nil {
+		t.Error("spec.timezone together with a CRON_TZ prefix was accepted")
+	}
+}
+
+func TestParseSchedule(t *testing.T) {
+	from := time.Date(2025, 6, 1, 10, 0, 0, 0, time.UTC)
+	tests := []struct {
+		schedule string
+		want     time.Time
+		wantErr  bool
+	}{
+		{schedule: "*/15 * * * *", want: from.Add(15 * time.Minute)},
+		{schedule: "30 */15 * * * *", want: from.Add(30 * time.Second)},
+		{schedule: "0 0 12 * * *", want: from.Add(2 * time.Hour)},
+		{schedule: "CRON_TZ=UTC */10 * * * * *", want: from.Add(10 * time.Second)},
+		{schedule: "@every 90s", want: from.Add(90 * time.Second)},
+		{schedule: "@hourly", want: from.Add(time.Hour)},
+		{schedule: "* * * *", wantErr: true},
+		{schedule: "0 0 0 * * * *", wantErr: true},
+		{schedule: "60 * * * * *", wantErr: true},
+	}
+	for _, tt := range tests {
+		sched, err := parseSchedule(tt.schedule)
+		if tt.wantErr {
+			if err == nil {
+				t.Errorf("parseSchedule(%q) succeeded, want an error", tt.schedule)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("parseSchedule(%q): %v", tt.schedule, err)
+			continue
+		}
+		if got := sched.Next(from); !got.Equal(tt.want) {
+			t.Errorf("parseSchedule(%q).Next = %s, want %s", tt.schedule, got, tt.want)
+		}
+	}
+}
+
+func TestBuildSchedulesExportSchedules(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	tlsData := map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}
+	validated := newExport("backend", "app", "app-tls")
+	setString(validated.Object, "spec.schedule", "0 6 * * *")
+	setString(validated.Object, "spec.timezone", "UTC")
+	pushed := newPushExport("backend", "ca", "ca-tls", "ca-tls", "api")
+	setString(pushed.Object, "spec.schedule", "30 * * * *")
+	setString(pushed.Object, "spec.timezone", "UTC")
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, tlsData),
+		newSecret("backend", "ca-tls", corev1.SecretTypeTLS, tlsData),
+		validated, pushed,
+	)
+	ctx := context.Background()
+	exportNamed := func(name string) *unstructured.Unstructured {
+		t.Helper()
+		exp := &unstructured.Unstructured{}
+		exp.SetGroupVersionKind(schemaGVK("CertificateExport"))
+		if err := c.Get(ctx, types.NamespacedName{Namespace: "backend", Name: name}, exp); err != nil {
+			t.Fatal(err)
+		}
+		return exp
+	}
+	nextSync := func(name string) time.Time {
+		t.Helper()
+		next, err := time.Parse(time.RFC3339, getString(exportNamed(name).Object, "status.nextSyncTime"))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		return next
+	}
+	runEntry := func(name string) {
+		t.Helper()
+		entry, ok := s.scheduled[scheduleKey("CertificateExport", "backend", name)]
+		if !ok {
+			t.Fatalf("export %s is not scheduled", name)
+		}
+		s.cron.Entry(entry.id).Job.Run()
+	}
+
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if next := nextSync("app"); next.Hour() != 6 || next.Minute() != 0 || !next.After(time.Now()) {
+		t.Errorf("got nextSyncTime %v for the validated export, want the next 06:00", next)
+	}
+	if next := nextSync("ca"); next.Minute() != 30 || !next.After(time.Now()) {
+		t.Errorf("got nextSyncTime %v for the pushing export, want the next half hour", next)
+	}
+
+	// the scheduled run of a pushing export pushes its secret
+	if getSecret(t, c, "api", "ca-tls") != nil {
+		t.Fatal("pushed before the schedule fired")
+	}
+	runEntry("ca")
+	if getSecret(t, c, "api", "ca-tls") == nil {
+		t.Error("the scheduled run did not push the secret")
+	}
+
+	// the scheduled run of any other export validates its source
+	eventually(t, "the new export is validated", func() bool {
+		cond := getExportCondition(t, c, "backend", "app", conditionSourceValid)
+		return cond != nil && cond.Status == metav1.ConditionTrue
+	})
+	if err := c.Delete(ctx, newSecret("backend", "app-tls", corev1.SecretTypeTLS, nil)); err != nil {
+		t.Fatal(err)
+	}
+	runEntry("app")
+	if cond := getExportCondition(t, c, "backend", "app", conditionSourceValid); cond == nil || cond.Status != metav1.ConditionFalse {
+		t.Errorf("got SourceValid condition %+v after the source was deleted, want False", cond)
+	}
+
+	// a new schedule replaces the entry and the next run
+	exp := exportNamed("app")
+	setString(exp.Object, "spec.schedule", "15 18 * * *")
+	if err := c.Update(ctx, exp); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if next := nextSync("app"); next.Hour() != 18 || next.Minute() != 15 {
+		t.Errorf("got nextSyncTime %v after the schedule changed, want the next 18:15", next)
+	}
+}
@@ -0,0 +1,92 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"errors"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// syncNowAnnotation on an import requests a one-off sync whenever its value,
+// e.g. a timestamp, changes. The last value acted upon is kept in
+// status.lastSyncNow.
+const syncNowAnnotation = annotationPrefix + "sync-now"
+
+// pendingSyncNow returns the sync-now value of an import that has not been
+// acted upon yet, or "".
+func pendingSyncNow(imp *unstructured.Unstructured) string {
+	value := imp.GetAnnotations()[syncNowAnnotation]
+	if value == "" || value == getString(imp.Object, "status.lastSyncNow") {
+		return ""
+	}
+	return value
+}
+
+// syncNowPredicate passes imports with a pending sync-now request, so the
+// status write recording it does not trigger another sync.
+func syncNowPredicate() predicate.Funcs {
+	pending := func(obj client.Object) bool {
+		imp, ok := obj.(*unstructured.Unstructured)
+		return ok && pendingSyncNow(imp) != ""
+	}
+	return predicate.Funcs{
+		CreateFunc:  func(e event.CreateEvent) bool { return pending(e.Object) },
+		UpdateFunc:  func(e event.UpdateEvent) bool { return pending(e.ObjectNew) },
+		DeleteFunc:  func(event.DeleteEvent) bool { return false },
+		GenericFunc: func(event.GenericEvent) bool { return false },
+	}
+}
+
+// syncNowReconciler runs a sync of an import as soon as its sync-now
+// annotation gets a new value, independently of its schedule.
+type syncNowReconciler struct {
+	s *SyncController
+}
+
+func (r *syncNowReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	imp := &unstructured.Unstructured{}
+	imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+	if err := r.s.Get(ctx, req.NamespacedName, imp); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+	value := pendingSyncNow(imp)
+	if value == "" || !r.s.namespaceAllowed(imp.GetNamespace()) {
+		return reconcile.Result{}, nil
+	}
+	logger := log.FromContext(ctx).WithValues("import", req.String())
+	logger.Info("sync requested by annotation, syncing import", "syncNow", value)
+	// runImportSync schedules its own retries; requeueing here would double
+	// them. A sync already in progress may predate the request, though, so
+	// it is only recorded once a sync of its own ran.
+	if err := r.s.runImportSync(ctx, req.Namespace, req.Name); errors.Is(err, errSyncInProgress) {
+		logger.V(1).Info("import sync in progress, requeueing sync request")
+		return reconcile.Result{Requeue: true}, nil
+	} else if err != nil {
+		logger.Error(err, "failed to sync import")
+	}
+	// recorded even after a failed sync, whose retries runImportSync owns
+	_ = r.s.updateStatus(ctx, imp, func(imp *unstructured.Unstructured) bool {
+		setString(imp.Object, "status.lastSyncNow", value)
+		return true
+	})
+	return reconcile.Result{}, nil
+}
@@ -0,0 +1,108 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestPendingSyncNow(t *testing.T) {
+	tests := []struct {
+		name       string
+		annotation string
+		last       string
+		want       string
+	}{
+		{name: "no request"},
+		{name: "new request", annotation: "2025-06-01T10:00:00Z", want: "2025-06-01T10:00:00Z"},
+		{name: "changed request", annotation: "2025-06-02T10:00:00Z", last: "2025-06-01T10:00:00Z", want: "2025-06-02T10:00:00Z"},
+		{name: "acted upon", annotation: "2025-06-01T10:00:00Z", last: "2025-06-01T10:00:00Z"},
+	}
+	for _, tt := range tests {
+		imp := newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app"})
+		if tt.annotation != "" {
+			imp.SetAnnotations(map[string]string{syncNowAnnotation: tt.annotation})
+		}
+		if tt.last != "" {
+			setString(imp.Object, "status.lastSyncNow", tt.last)
+		}
+		if got := pendingSyncNow(imp); got != tt.want {
+			t.Errorf("%s: got %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestSyncNowReconciler(t *testing.T) {
+	crt, key := newKeyPair(t, "app")
+	imp := newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"})
+	imp.SetAnnotations(map[string]string{syncNowAnnotation: "1"})
+	s, c := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		imp,
+	)
+	ctx := context.Background()
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "frontend", Name: "app"}}
+	lastSyncNow := func() string {
+		imp := &unstructured.Unstructured{}
+		imp.SetGroupVersionKind(schemaGVK("CertificateImport"))
+		if err := c.Get(ctx, req.NamespacedName, imp); err != nil {
+			t.Fatal(err)
+		}
+		return getString(imp.Object, "status.lastSyncNow")
+	}
+	r := &syncNowReconciler{s: s}
+
+	// a sync already in progress may predate the request
+	s.retryMu.Lock()
+	s.retries["frontend/app"] = &retryState{running: true}
+	s.retryMu.Unlock()
+	res, err := r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !res.Requeue {
+		t.Error("a request arriving during a sync was not requeued")
+	}
+	if got := lastSyncNow(); got != "" {
+		t.Errorf("got lastSyncNow %q before a sync ran, want it unset", got)
+	}
+	if getSecret(t, c, "frontend", "app-tls") != nil {
+		t.Fatal("the target was written by the skipped sync")
+	}
+
+	s.retryMu.Lock()
+	delete(s.retries, "frontend/app")
+	s.retryMu.Unlock()
+	res, err = r.Reconcile(ctx, req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Requeue {
+		t.Error("a handled request was requeued")
+	}
+	if got := lastSyncNow(); got != "1" {
+		t.Errorf("got lastSyncNow %q, want %q", got, "1")
+	}
+	if getSecret(t, c, "frontend", "app-tls") == nil {
+		t.Error("the requested sync did not write the target")
+	}
+}
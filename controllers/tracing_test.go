@@ -0,0 +1,106 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// recordSpans routes the spans of the package to an in-memory recorder for
+// the duration of the test.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	sr := tracetest.NewSpanRecorder()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))
+	prev := tracer
+	tracer = tp.Tracer("test")
+	t.Cleanup(func() { tracer = prev })
+	return sr
+}
+
+// spanAttr returns the value of the attribute key of span, or "".
+func spanAttr(span sdktrace.ReadOnlySpan, key attribute.Key) string {
+	for _, kv := range span.Attributes() {
+		if kv.Key == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestSyncSpans(t *testing.T) {
+	sr := recordSpans(t)
+	crt, key := newKeyPair(t, "app")
+	s, _ := newTestController(t, Options{},
+		newSecret("backend", "app-tls", corev1.SecretTypeTLS, map[string][]byte{corev1.TLSCertKey: crt, corev1.TLSPrivateKeyKey: key}),
+		newExport("backend", "app", "app-tls"),
+		newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		newImport("frontend", "broken", map[string]interface{}{"fromExport": "backend/missing", "targetSecret": "broken-tls"}),
+	)
+	ctx := context.Background()
+	if err := s.buildSchedules(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := s.syncImport(ctx, "frontend", "app"); err != nil {
+		t.Fatal(err)
+	}
+	_ = s.syncImport(ctx, "frontend", "broken")
+
+	spans := sr.Ended()
+	byName := map[string][]sdktrace.ReadOnlySpan{}
+	for _, span := range spans {
+		byName[span.Name()] = append(byName[span.Name()], span)
+	}
+	if len(byName["buildSchedules"]) != 1 {
+		t.Errorf("got %d buildSchedules spans, want 1", len(byName["buildSchedules"]))
+	}
+	imports := byName["syncImport"]
+	if len(imports) != 2 {
+		t.Fatalf("got %d syncImport spans, want 2", len(imports))
+	}
+	tests := []struct {
+		span       sdktrace.ReadOnlySpan
+		name       string
+		wantResult string
+		wantCode   codes.Code
+	}{
+		{span: imports[0], name: "app", wantResult: "success", wantCode: codes.Unset},
+		{span: imports[1], name: "broken", wantResult: "error", wantCode: codes.Error},
+	}
+	for _, tt := range tests {
+		if got := spanAttr(tt.span, "import.namespace"); got != "frontend" {
+			t.Errorf("%s: got import.namespace %q, want frontend", tt.name, got)
+		}
+		if got := spanAttr(tt.span, "import.name"); got != tt.name {
+			t.Errorf("%s: got import.name %q, want %s", tt.name, got, tt.name)
+		}
+		if got := spanAttr(tt.span, "result"); got != tt.wantResult {
+			t.Errorf("%s: got result %q, want %s", tt.name, got, tt.wantResult)
+		}
+		if got := tt.span.Status().Code; got != tt.wantCode {
+			t.Errorf("%s: got status %v, want %v", tt.name, got, tt.wantCode)
+		}
+	}
+	if got := spanAttr(imports[0], "source.secret"); got != "backend/app-tls" {
+		t.Errorf("got source.secret %q, want backend/app-tls", got)
+	}
+}
@@ -0,0 +1,135 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel/attribute"
+	certificatesv1alpha1 "k8s.io/api/certificates/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// clusterTrustBundlesServed reports whether the API server serves
+// certificates.k8s.io/v1alpha1 ClusterTrustBundle.
+func clusterTrustBundlesServed(mapper meta.RESTMapper) bool {
+	gvk := certificatesv1alpha1.SchemeGroupVersion.WithKind("ClusterTrustBundle")
+	_, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	return err == nil
+}
+
+// syncClusterTrustBundle writes the ca.crt of the source secret of a
+// ClusterCertificateExport into the ClusterTrustBundle named by
+// spec.clusterTrustBundle.name, so kubelets can project it into pods.
+func (s *SyncController) syncClusterTrustBundle(ctx context.Context, name string) (err error) {
+	ctx, span := startSpan(ctx, "syncClusterTrustBundle", attribute.String("clusterExport.name", name))
+	defer func() { endSpan(span, err) }()
+	logger := log.FromContext(ctx).WithValues("clusterExport", name)
+	release, err := s.acquireSync(ctx, scheduleKey("ClusterCertificateExport", "", name))
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	exp := &unstructured.Unstructured{}
+	exp.SetGroupVersionKind(schemaGVK("ClusterCertificateExport"))
+	if err := s.Get(ctx, types.NamespacedName{Name: name}, exp); err != nil {
+		logger.Error(err, "failed to get cluster export")
+		return err
+	}
+	defer func() { s.recordSyncResult(exp, err, "published cluster trust bundle") }()
+
+	srcKey := exportSource(exp)
+	var src corev1.Secret
+	if err := s.getSourceSecret(ctx, srcKey, &src); err != nil {
+		logger.Error(err, "failed to get source secret", "secretRef", srcKey.Name, "namespace", srcKey.Namespace)
+		return err
+	}
+	if err := checkSourceType(exp, &src); err != nil {
+		return err
+	}
+	ca := src.Data["ca.crt"]
+	if len(ca) == 0 {
+		return fmt.Errorf("source secret %s of cluster export %s has no ca.crt", srcKey, name)
+	}
+	bundle, count, err := buildCABundle(ca)
+	if err != nil {
+		return fmt.Errorf("cluster export %s: %w", name, err)
+	}
+
+	ctbName := getString(exp.Object, "spec.clusterTrustBundle.name")
+	desired := certificatesv1alpha1.ClusterTrustBundleSpec{
+		SignerName:  getString(exp.Object, "spec.clusterTrustBundle.signerName"),
+		TrustBundle: string(bundle),
+	}
+	owner := clusterExportPrefix + name
+	var ctb certificatesv1alpha1.ClusterTrustBundle
+	err = s.Get(ctx, types.NamespacedName{Name: ctbName}, &ctb)
+	switch {
+	case apierrors.IsNotFound(err):
+		ctb = certificatesv1alpha1.ClusterTrustBundle{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:        ctbName,
+				Annotations: map[string]string{managedByAnnotation: owner},
+			},
+			Spec: desired,
+		}
+		if err := controllerutil.SetControllerReference(exp, &ctb, s.scheme); err != nil {
+			return err
+		}
+		if err := s.Create(ctx, &ctb); err != nil {
+			logger.Error(err, "failed to create cluster trust bundle", "clusterTrustBundle", ctbName)
+			return err
+		}
+		logger.Info("created cluster trust bundle", "clusterTrustBundle", ctbName, "certificates", count)
+	case err != nil:
+		return err
+	default:
+		if managedBy := ctb.Annotations[managedByAnnotation]; managedBy != owner {
+			err := fmt.Errorf("%w: cluster trust bundle %s is not managed by cluster export %s (managed-by: %q)", ErrTargetNotManaged, ctbName, name, managedBy)
+			logger.Error(err, "refusing to overwrite cluster trust bundle")
+			_ = s.updateStatus(ctx, exp, func(exp *unstructured.Unstructured) bool {
+				return setCondition(exp, conditionConflict, metav1.ConditionTrue, reasonTargetNotManaged, err.Error())
+			})
+			return err
+		}
+		if ctb.Spec == desired {
+			logger.V(1).Info("cluster trust bundle up to date, skipping update", "clusterTrustBundle", ctbName)
+			break
+		}
+		ctb.Spec = desired
+		if err := s.Update(ctx, &ctb); err != nil {
+			logger.Error(err, "failed to update cluster trust bundle", "clusterTrustBundle", ctbName)
+			return err
+		}
+		logger.Info("updated cluster trust bundle", "clusterTrustBundle", ctbName, "certificates", count)
+	}
+
+	// Record the sync in the status of the cluster export (best-effort)
+	_ = s.updateStatus(ctx, exp, func(exp *unstructured.Unstructured) bool {
+		markSynced(exp)
+		removeConditionWithReason(exp, conditionConflict, reasonTargetNotManaged)
+		return true
+	})
+	return nil
+}
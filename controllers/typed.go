@@ -0,0 +1,55 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"fmt"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+
+	certtrustv1 "github.com/nazman/cert-trust/api/v1"
+)
+
+// toImport converts an import read through the unstructured client into the
+// typed API, so its spec is read through fields the compiler checks. Status
+// and conditions are still written on the unstructured object.
+func toImport(u *unstructured.Unstructured) (*certtrustv1.CertificateImport, error) {
+	imp := &certtrustv1.CertificateImport{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, imp); err != nil {
+		return nil, fmt.Errorf("invalid CertificateImport %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	return imp, nil
+}
+
+// toExport converts an export read through the unstructured client into the
+// typed API.
+func toExport(u *unstructured.Unstructured) (*certtrustv1.CertificateExport, error) {
+	exp := &certtrustv1.CertificateExport{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, exp); err != nil {
+		return nil, fmt.Errorf("invalid CertificateExport %s/%s: %w", u.GetNamespace(), u.GetName(), err)
+	}
+	return exp, nil
+}
+
+// toClusterExport converts a cluster export read through the unstructured
+// client into the typed API.
+func toClusterExport(u *unstructured.Unstructured) (*certtrustv1.ClusterCertificateExport, error) {
+	exp := &certtrustv1.ClusterCertificateExport{}
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, exp); err != nil {
+		return nil, fmt.Errorf("invalid ClusterCertificateExport %s: %w", u.GetName(), err)
+	}
+	return exp, nil
+}
@@ -0,0 +1,102 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func TestToImport(t *testing.T) {
+	imp, err := toImport(newImport("frontend", "app", map[string]interface{}{
+		"fromExport":    "backend/app",
+		"targetSecret":  "app-tls",
+		"schedule":      "*/5 * * * *",
+		"jitter":        "90s",
+		"includeKeys":   []interface{}{"tls.crt", "tls.key"},
+		"verifyKeyPair": false,
+		"pkcs12":        map[string]interface{}{"passwordSecretRef": map[string]interface{}{"name": "pass"}},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec := imp.Spec
+	if spec.FromExport != "backend/app" || spec.TargetSecret != "app-tls" || spec.Schedule != "*/5 * * * *" {
+		t.Errorf("got spec %+v", spec)
+	}
+	if spec.Jitter == nil || spec.Jitter.Duration != 90*time.Second {
+		t.Errorf("got jitter %v, want 90s", spec.Jitter)
+	}
+	if len(spec.IncludeKeys) != 2 || spec.VerifyKeyPair == nil || *spec.VerifyKeyPair {
+		t.Errorf("got includeKeys %v and verifyKeyPair %v", spec.IncludeKeys, spec.VerifyKeyPair)
+	}
+	if spec.PKCS12 == nil || spec.PKCS12.PasswordSecretRef.Name != "pass" {
+		t.Errorf("got pkcs12 %+v", spec.PKCS12)
+	}
+	if imp.Namespace != "frontend" || imp.Name != "app" || imp.UID != "frontend-app" {
+		t.Errorf("got metadata %s/%s %s", imp.Namespace, imp.Name, imp.UID)
+	}
+}
+
+func TestToTypedRejectsSchemaDrift(t *testing.T) {
+	// fields of the wrong type, which the string helpers read as ""
+	tests := []struct {
+		name    string
+		convert func() error
+	}{
+		{
+			name: "import schedule as a number",
+			convert: func() error {
+				_, err := toImport(newImport("frontend", "app", map[string]interface{}{"schedule": int64(5)}))
+				return err
+			},
+		},
+		{
+			name: "import includeKeys as a string",
+			convert: func() error {
+				_, err := toImport(newImport("frontend", "app", map[string]interface{}{"includeKeys": "tls.crt"}))
+				return err
+			},
+		},
+		{
+			name: "export secretRef as a map",
+			convert: func() error {
+				exp := newExport("backend", "app", "")
+				exp.Object["spec"] = map[string]interface{}{"secretRef": map[string]interface{}{"name": "app-tls"}}
+				_, err := toExport(exp)
+				return err
+			},
+		},
+		{
+			name: "cluster export clusterTrustBundle as a string",
+			convert: func() error {
+				exp := &unstructured.Unstructured{Object: map[string]interface{}{"spec": map[string]interface{}{"clusterTrustBundle": "root-ca"}}}
+				exp.SetGroupVersionKind(schemaGVK("ClusterCertificateExport"))
+				exp.SetName("root-ca")
+				_, err := toClusterExport(exp)
+				return err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.convert(); err == nil {
+				t.Error("converted an object of the wrong shape")
+			}
+		})
+	}
+}
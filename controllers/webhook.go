@@ -0,0 +1,291 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// validatePath is where the validating webhook for both CRDs is served.
+const validatePath = "/validate-cert-trust-flolive-io-v1"
+
+// convertPath is where the conversion webhook between CertificateImport
+// versions is served.
+const convertPath = "/convert"
+
+// forceDeleteAnnotation on an export allows deleting it while imports still
+// reference it.
+const forceDeleteAnnotation = annotationPrefix + "force-delete"
+
+// admissionValidator rejects CertificateImports and CertificateExports that
+// the controller would not be able to act on.
+type admissionValidator struct {
+	client.Reader
+}
+
+func (v *admissionValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Operation == admissionv1.Delete {
+		obj := &unstructured.Unstructured{}
+		if err := obj.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+		if err := v.validateDelete(ctx, obj); err != nil {
+			return admission.Denied(err.Error())
+		}
+		return admission.Allowed("")
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON(req.Object.Raw); err != nil {
+		return admission.Errored(http.StatusBadRequest, err)
+	}
+	// an object being deleted only loses its finalizers, which must never
+	// hang on references that are gone by now
+	if obj.GetDeletionTimestamp() != nil {
+		return admission.Allowed("")
+	}
+	var old *unstructured.Unstructured
+	if req.Operation == admissionv1.Update && len(req.OldObject.Raw) > 0 {
+		old = &unstructured.Unstructured{}
+		if err := old.UnmarshalJSON(req.OldObject.Raw); err != nil {
+			return admission.Errored(http.StatusBadRequest, err)
+		}
+	}
+	if err := v.validate(ctx, obj, old); err != nil {
+		return admission.Denied(err.Error())
+	}
+	return admission.Allowed("")
+}
+
+// validate checks obj on create, or on update from old. Export references
+// are only resolved when they are new, so an update that leaves them alone
+// is not rejected because an export was deleted in the meantime.
+func (v *admissionValidator) validate(ctx context.Context, obj, old *unstructured.Unstructured) error {
+	if err := validateRequiredFields(obj); err != nil {
+		return err
+	}
+	schedule, err := scheduleSpec(obj)
+	if err != nil {
+		return err
+	}
+	if _, err := parseSchedule(schedule); err != nil {
+		return fmt.Errorf("invalid spec.schedule %q: %v", getString(obj.Object, "spec.schedule"), err)
+	}
+	if obj.GetKind() == "CertificateImport" {
+		if jitter := getString(obj.Object, "spec.jitter"); jitter != "" {
+			if d, err := time.ParseDuration(jitter); err != nil || d < 0 {
+				return fmt.Errorf("invalid spec.jitter %q: must be a non-negative duration", jitter)
+			}
+		}
+		if _, err := renderKeyMap(getStringMap(obj.Object, "spec.keyMap"), obj.GetNamespace(), obj.GetName()); err != nil {
+			return fmt.Errorf("invalid spec.keyMap: %v", err)
+		}
+		if fp := getString(obj.Object, "spec.expectedFingerprint"); fp != "" {
+			if b, err := hex.DecodeString(normalizeFingerprint(fp)); err != nil || len(b) != sha256.Size {
+				return fmt.Errorf("invalid spec.expectedFingerprint %q: must be a hex SHA-256, optionally colon-separated", fp)
+			}
+		}
+		var unchanged []string
+		if old != nil {
+			unchanged = importExportRefs(old)
+		}
+		for _, ref := range importExportRefs(obj) {
+			if slices.Contains(unchanged, ref) {
+				continue
+			}
+			if err := v.validateFromExport(ctx, obj, ref); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// validateDelete refuses to delete an export that imports still reference,
+// unless it carries forceDeleteAnnotation or its namespace is being deleted,
+// which would otherwise hang on imports in other namespaces.
+func (v *admissionValidator) validateDelete(ctx context.Context, exp *unstructured.Unstructured) error {
+	kind := exp.GetKind()
+	if kind != "CertificateExport" && kind != "ClusterCertificateExport" {
+		return nil
+	}
+	if exp.GetAnnotations()[forceDeleteAnnotation] == "true" {
+		return nil
+	}
+	if ns := exp.GetNamespace(); ns != "" {
+		var namespace corev1.Namespace
+		if err := v.Get(ctx, types.NamespacedName{Name: ns}, &namespace); err == nil && !namespace.DeletionTimestamp.IsZero() {
+			return nil
+		}
+	}
+	imports := &unstructured.UnstructuredList{}
+	imports.SetGroupVersionKind(schemaGVKList("CertificateImport"))
+	if err := v.List(ctx, imports); err != nil {
+		return fmt.Errorf("failed to list CertificateImports referencing %s %s: %v", kind, exp.GetName(), err)
+	}
+	importers := indexImporters(imports.Items)[exportID(kind, types.NamespacedName{Namespace: exp.GetNamespace(), Name: exp.GetName()})]
+	if len(importers) == 0 {
+		return nil
+	}
+	names := make([]string, len(importers))
+	for i, imp := range importers {
+		names[i] = imp.String()
+	}
+	name := exp.GetName()
+	if ns := exp.GetNamespace(); ns != "" {
+		name = ns + "/" + name
+	}
+	return fmt.Errorf("%s %s is still referenced by CertificateImports %s; delete them first or annotate the export with %s=true", kind, name, strings.Join(names, ", "), forceDeleteAnnotation)
+}
+
+// validateRequiredFields checks that the names and references the controller
+// resolves at sync time are set and well-formed, so that mistakes surface at
+// apply time rather than as failing syncs.
+func validateRequiredFields(obj *unstructured.Unstructured) error {
+	switch obj.GetKind() {
+	case "CertificateExport":
+		return validateName("spec.secretRef", getString(obj.Object, "spec.secretRef"))
+	case "ClusterCertificateExport":
+		ns := getString(obj.Object, "spec.sourceNamespace")
+		if ns == "" {
+			return fmt.Errorf("spec.sourceNamespace must not be empty: set it to the namespace of the source secret")
+		}
+		if errs := validation.IsDNS1123Label(ns); len(errs) > 0 {
+			return fmt.Errorf("invalid spec.sourceNamespace %q: must be a valid namespace name: %s", ns, strings.Join(errs, ", "))
+		}
+		return validateName("spec.secretRef", getString(obj.Object, "spec.secretRef"))
+	case "CertificateImport":
+		if ref := getString(obj.Object, "spec.fromExport"); ref != "" {
+			if err := validateExportRef("spec.fromExport", obj.GetNamespace(), ref); err != nil {
+				return err
+			}
+		}
+		for i, ref := range getStringSlice(obj.Object, "spec.fromExports") {
+			if err := validateExportRef(fmt.Sprintf("spec.fromExports[%d]", i), obj.GetNamespace(), ref); err != nil {
+				return err
+			}
+		}
+		if getString(obj.Object, "spec.targetConfigMap") != "" {
+			return validateName("spec.targetConfigMap", getString(obj.Object, "spec.targetConfigMap"))
+		}
+		if err := validateImportTargets(obj); err != nil {
+			return err
+		}
+		return validateName("spec.targetSecret", getString(obj.Object, "spec.targetSecret"))
+	}
+	return nil
+}
+
+// validateName checks that value, the name of a secret or configmap set in
+// field, is a non-empty RFC 1123 subdomain.
+func validateName(field, value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("%s must not be empty", field)
+	}
+	if errs := validation.IsDNS1123Subdomain(value); len(errs) > 0 {
+		return fmt.Errorf("invalid %s %q: must be a valid object name: %s", field, value, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateExportRef checks the syntax of an export reference in field,
+// including the namespace and name it resolves to.
+func validateExportRef(field, namespace, ref string) error {
+	_, key, err := exportKind(namespace, ref)
+	if err != nil {
+		return fmt.Errorf("invalid %s: %v; use <name>, <namespace>/<name> or %s<name>", field, err, clusterExportPrefix)
+	}
+	if key.Namespace != "" {
+		if errs := validation.IsDNS1123Label(key.Namespace); len(errs) > 0 {
+			return fmt.Errorf("invalid %s %q: namespace %q is not a valid namespace name: %s", field, ref, key.Namespace, strings.Join(errs, ", "))
+		}
+	}
+	if errs := validation.IsDNS1123Subdomain(key.Name); len(errs) > 0 {
+		return fmt.Errorf("invalid %s %q: export name %q is not a valid object name: %s", field, ref, key.Name, strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// validateImportTargets checks that an import either copies one primary
+// export into a secret, optionally merging the CAs of further exports, or
+// bundles CAs of one or more exports into a configmap.
+func validateImportTargets(imp *unstructured.Unstructured) error {
+	if getString(imp.Object, "spec.targetConfigMap") != "" {
+		if len(importExportRefs(imp)) == 0 {
+			return fmt.Errorf("spec.targetConfigMap requires spec.fromExport or spec.fromExports")
+		}
+		return nil
+	}
+	if fromExportOf(imp) == "" || getString(imp.Object, "spec.targetSecret") == "" {
+		if len(getStringSlice(imp.Object, "spec.fromExports")) > 0 {
+			return fmt.Errorf("spec.fromExport must name the primary export supplying tls.crt and tls.key when spec.fromExports is used with spec.targetSecret")
+		}
+		return fmt.Errorf("spec.fromExport and spec.targetSecret are required unless spec.targetConfigMap is set or the controller has a --default-from-export")
+	}
+	// a pull secret has no certificate to bundle, split or convert
+	if getString(imp.Object, "spec.targetType") == string(corev1.SecretTypeDockerConfigJson) {
+		_, pkcs12 := getValue(imp.Object, "spec.pkcs12")
+		_, jks := getValue(imp.Object, "spec.jks")
+		for _, f := range []struct {
+			field string
+			set   bool
+		}{
+			{"fromExports", len(getStringSlice(imp.Object, "spec.fromExports")) > 0},
+			{"splitCABundle", getBool(imp.Object, "spec.splitCABundle", false)},
+			{"pkcs12", pkcs12},
+			{"jks", jks},
+		} {
+			if f.set {
+				return fmt.Errorf("spec.%s cannot be used with spec.targetType %s", f.field, corev1.SecretTypeDockerConfigJson)
+			}
+		}
+	}
+	return nil
+}
+
+// validateFromExport checks that fromExport names an existing export the
+// controller is allowed to read.
+func (v *admissionValidator) validateFromExport(ctx context.Context, imp *unstructured.Unstructured, fromExport string) error {
+	kind, key, err := exportKind(imp.GetNamespace(), fromExport)
+	if err != nil {
+		return err
+	}
+	_, err = getExport(ctx, v, imp.GetNamespace(), fromExport)
+	switch {
+	case err == nil:
+		return nil
+	case apierrors.IsNotFound(err):
+		return fmt.Errorf("referenced %s %s not found", kind, key)
+	case apierrors.IsForbidden(err):
+		return fmt.Errorf("cert-trust is not permitted to read referenced %s %s", kind, key)
+	default:
+		return fmt.Errorf("failed to resolve referenced %s %s: %v", kind, key, err)
+	}
+}
@@ -0,0 +1,266 @@
+// Copyright 2025 cert-trust contributors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// admissionRequest returns a request for op on obj, replacing old.
+func admissionRequest(t *testing.T, op admissionv1.Operation, obj, old *unstructured.Unstructured) admission.Request {
+	t.Helper()
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{Operation: op}}
+	for _, o := range []struct {
+		u   *unstructured.Unstructured
+		raw *runtime.RawExtension
+	}{{obj, &req.Object}, {old, &req.OldObject}} {
+		if o.u == nil {
+			continue
+		}
+		raw, err := o.u.MarshalJSON()
+		if err != nil {
+			t.Fatal(err)
+		}
+		o.raw.Raw = raw
+	}
+	return req
+}
+
+func TestAdmissionValidatorFromExport(t *testing.T) {
+	spec := func(fromExport string) map[string]interface{} {
+		return map[string]interface{}{"fromExport": fromExport, "targetSecret": "app-tls"}
+	}
+	deleting := newImport("frontend", "app", spec("backend/gone"))
+	now := metav1.Now()
+	deleting.SetDeletionTimestamp(&now)
+	deleting.SetFinalizers([]string{cleanupFinalizer})
+	deletingOld := deleting.DeepCopy()
+	deleting.SetFinalizers(nil)
+
+	tests := []struct {
+		name    string
+		op      admissionv1.Operation
+		obj     *unstructured.Unstructured
+		old     *unstructured.Unstructured
+		wantMsg string
+	}{
+		{
+			name: "same-namespace export exists",
+			op:   admissionv1.Create,
+			obj:  newImport("frontend", "app", spec("local")),
+		},
+		{
+			name: "cross-namespace export exists",
+			op:   admissionv1.Create,
+			obj:  newImport("frontend", "app", spec("backend/app")),
+		},
+		{
+			name:    "same-namespace export missing",
+			op:      admissionv1.Create,
+			obj:     newImport("frontend", "app", spec("missing")),
+			wantMsg: "referenced CertificateExport frontend/missing not found",
+		},
+		{
+			name:    "cross-namespace export missing",
+			op:      admissionv1.Create,
+			obj:     newImport("frontend", "app", spec("prod/ca")),
+			wantMsg: "referenced CertificateExport prod/ca not found",
+		},
+		{
+			name: "update keeping a reference to a deleted export",
+			op:   admissionv1.Update,
+			obj: func() *unstructured.Unstructured {
+				imp := newImport("frontend", "app", spec("backend/gone"))
+				imp.SetLabels(map[string]string{"team": "web"})
+				return imp
+			}(),
+			old: newImport("frontend", "app", spec("backend/gone")),
+		},
+		{
+			name:    "update pointing to a missing export",
+			op:      admissionv1.Update,
+			obj:     newImport("frontend", "app", spec("backend/gone")),
+			old:     newImport("frontend", "app", spec("backend/app")),
+			wantMsg: "referenced CertificateExport backend/gone not found",
+		},
+		{
+			name: "finalizer removal of an import whose export is gone",
+			op:   admissionv1.Update,
+			obj:  deleting,
+			old:  deletingOld,
+		},
+	}
+	_, c := newTestController(t, Options{},
+		newExport("frontend", "local", "local-tls"),
+		newExport("backend", "app", "app-tls"),
+	)
+	v := &admissionValidator{Reader: c}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), admissionRequest(t, tt.op, tt.obj, tt.old))
+			if tt.wantMsg == "" {
+				if !resp.Allowed {
+					t.Fatalf("denied: %s", resp.Result.Message)
+				}
+				return
+			}
+			if resp.Allowed {
+				t.Fatal("allowed, want denied")
+			}
+			if !strings.Contains(resp.Result.Message, tt.wantMsg) {
+				t.Errorf("got message %q, want it to contain %q", resp.Result.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestAdmissionValidatorSchedule(t *testing.T) {
+	withSchedule := func(obj *unstructured.Unstructured, schedule string) *unstructured.Unstructured {
+		setString(obj.Object, "spec.schedule", schedule)
+		return obj
+	}
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		wantMsg string
+	}{
+		{
+			name: "import with a valid schedule",
+			obj:  withSchedule(newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}), "0 */6 * * *"),
+		},
+		{
+			name: "import without a schedule",
+			obj:  newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}),
+		},
+		{
+			name:    "import with an invalid schedule",
+			obj:     withSchedule(newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}), "every day"),
+			wantMsg: `invalid spec.schedule "every day"`,
+		},
+		{
+			name:    "import with an out of range field",
+			obj:     withSchedule(newImport("frontend", "app", map[string]interface{}{"fromExport": "backend/app", "targetSecret": "app-tls"}), "0 25 * * *"),
+			wantMsg: `invalid spec.schedule "0 25 * * *"`,
+		},
+		{
+			name: "export with a valid schedule",
+			obj:  withSchedule(newExport("backend", "app", "app-tls"), "@daily"),
+		},
+		{
+			name:    "export with an invalid schedule",
+			obj:     withSchedule(newExport("backend", "app", "app-tls"), "* * *"),
+			wantMsg: `invalid spec.schedule "* * *"`,
+		},
+	}
+	_, c := newTestController(t, Options{}, newExport("backend", "app", "app-tls"))
+	v := &admissionValidator{Reader: c}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), admissionRequest(t, admissionv1.Create, tt.obj, nil))
+			if tt.wantMsg == "" {
+				if !resp.Allowed {
+					t.Fatalf("denied: %s", resp.Result.Message)
+				}
+				return
+			}
+			if resp.Allowed {
+				t.Fatal("allowed, want denied")
+			}
+			if !strings.Contains(resp.Result.Message, tt.wantMsg) {
+				t.Errorf("got message %q, want it to contain %q", resp.Result.Message, tt.wantMsg)
+			}
+		})
+	}
+}
+
+func TestAdmissionValidatorDelete(t *testing.T) {
+	spec := func(fromExport string) map[string]interface{} {
+		return map[string]interface{}{"fromExport": fromExport, "targetSecret": "app-tls"}
+	}
+	clusterExport := func(name string) *unstructured.Unstructured {
+		exp := newExport("", name, "root-ca")
+		exp.SetKind("ClusterCertificateExport")
+		return exp
+	}
+	forced := newExport("backend", "app", "app-tls")
+	forced.SetAnnotations(map[string]string{forceDeleteAnnotation: "true"})
+	now := metav1.Now()
+	tests := []struct {
+		name    string
+		obj     *unstructured.Unstructured
+		wantMsg string
+	}{
+		{
+			name:    "export with importers",
+			obj:     newExport("backend", "app", "app-tls"),
+			wantMsg: "CertificateExport backend/app is still referenced by CertificateImports frontend/app, web/app",
+		},
+		{
+			name: "export with importers and the force annotation",
+			obj:  forced,
+		},
+		{
+			name: "export without importers",
+			obj:  newExport("backend", "unused", "unused-tls"),
+		},
+		{
+			name: "export in a namespace being deleted",
+			obj:  newExport("retired", "app", "app-tls"),
+		},
+		{
+			name:    "cluster export with importers",
+			obj:     clusterExport("root-ca"),
+			wantMsg: "ClusterCertificateExport root-ca is still referenced by CertificateImports frontend/roots",
+		},
+		{
+			name: "import",
+			obj:  newImport("frontend", "app", spec("backend/app")),
+		},
+	}
+	_, c := newTestController(t, Options{},
+		newImport("frontend", "app", spec("backend/app")),
+		newImport("web", "app", spec("backend/app")),
+		newImport("frontend", "roots", spec("cluster/root-ca")),
+		newImport("frontend", "retired", spec("retired/app")),
+		&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "retired", DeletionTimestamp: &now, Finalizers: []string{"kubernetes"}}},
+	)
+	v := &admissionValidator{Reader: c}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := v.Handle(context.Background(), admissionRequest(t, admissionv1.Delete, nil, tt.obj))
+			if tt.wantMsg == "" {
+				if !resp.Allowed {
+					t.Fatalf("denied: %s", resp.Result.Message)
+				}
+				return
+			}
+			if resp.Allowed {
+				t.Fatal("allowed, want denied")
+			}
+			if !strings.Contains(resp.Result.Message, tt.wantMsg) {
+				t.Errorf("got message %q, want it to contain %q", resp.Result.Message, tt.wantMsg)
+			}
+		})
+	}
+}